@@ -0,0 +1,111 @@
+package docxplate
+
+import "maps"
+
+// CompiledTemplate - template parsed once and ready to be rendered many times.
+// Files with placeholders are read, parsed and fixed up only on compile,
+// every render works on its own copy of them, so the compiled template itself
+// never changes and can be rendered from multiple goroutines at once.
+type CompiledTemplate struct {
+	// snapshot of source template taken on compile: archive files are
+	// only read from it, formatters registered on source later are not seen
+	t *Template
+
+	// parsed and prepared files holding placeholders, read only
+	parts map[string]*xmlNode
+}
+
+// Document - result of a single CompiledTemplate render
+type Document struct {
+	t *Template
+}
+
+// Compile - parse template files once so it can be rendered many times
+//
+//	ctpl, _ := tdoc.Compile()
+//	for _, user := range users {
+//		doc, _ := ctpl.Render(user)
+//		doc.ExportDocx(user.Name + ".docx")
+//	}
+func (t *Template) Compile() (*CompiledTemplate, error) {
+	tcopy, parts, err := t.preparedParts()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CompiledTemplate{
+		t:     tcopy.renderCopy(),
+		parts: parts,
+	}, nil
+}
+
+// CompileTemplate - open local template file and compile it
+func CompileTemplate(docpath string, opts ...Option) (*CompiledTemplate, error) {
+	t, err := OpenTemplate(docpath, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return t.Compile()
+}
+
+// Render - replace placeholders with params on a fresh copy of compiled template.
+// Errors are the same as of Template.Render, document is returned
+// with them too, unless params can't be decoded at all
+func (ct *CompiledTemplate) Render(v any, opts ...Option) (*Document, error) {
+	t := ct.t.renderCopy()
+	if err := t.applyOptions(opts); err != nil {
+		return nil, err
+	}
+	if t.delims.open != ct.t.delims.open || t.delims.close != ct.t.delims.close {
+		return nil, ErrCompiledDelimiters
+	}
+
+	params, err := collectParams(v)
+	if err != nil {
+		return nil, err
+	}
+	t.setParams(params)
+
+	for fname, xnode := range ct.parts {
+		t.renderXML(fname, xnode.copyTree(nil))
+	}
+
+	return &Document{t: t}, t.renderErr()
+}
+
+// renderCopy - new template sharing (read only) archive of this template
+// but with its own render state and formatters
+func (t *Template) renderCopy() *Template {
+	return &Template{
+		path:                 t.path,
+		zipr:                 t.zipr,
+		files:                t.files,
+		documentContentTypes: t.documentContentTypes,
+		documentRels:         t.documentRels,
+		opts:                 t.opts,
+		delims:               t.delims,
+		formatters:           maps.Clone(t.formatters),
+		added:                map[string][]byte{},
+		modified:             map[string][]byte{},
+	}
+}
+
+// Bytes - create docx archive but return only bytes of it
+func (doc *Document) Bytes() ([]byte, error) {
+	return doc.t.Bytes()
+}
+
+// ExportDocx - save rendered document as docx
+func (doc *Document) ExportDocx(path string) error {
+	return doc.t.ExportDocx(path)
+}
+
+// Plaintext - return rendered document as plaintext
+func (doc *Document) Plaintext() string {
+	return string(doc.t.delimiters().unescape([]byte(doc.t.plaintext())))
+}
+
+// Placeholders - get list of placeholders left in rendered document
+func (doc *Document) Placeholders() []string {
+	return doc.t.delimiters().reParam.FindAllString(doc.t.plaintext(), -1)
+}
@@ -0,0 +1,127 @@
+package docxplate
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Default placeholder delimiters {{Key}}
+const (
+	DefaultOpenDelimiter  = "{{"
+	DefaultCloseDelimiter = "}}"
+)
+
+// escapeMark - prefix of open delimiter to keep it as text: \{{Name}} --> {{Name}}
+const escapeMark = `\`
+
+// Escaped open delimiter is kept as escapedOpen (private use chars) until
+// document is saved, so it's never taken for a placeholder. Text may hold
+// escapeChar itself (icon fonts), it's kept doubled meanwhile
+const (
+	escapeChar  = "\uE000"
+	escapedOpen = escapeChar + "\uE001"
+)
+
+// delimiters - placeholder open and close marks and regexes built for them
+type delimiters struct {
+	open  string
+	close string
+
+	reParam    *regexp.Regexp // placeholder with key, separator and params
+	reLike     *regexp.Regexp // any text in delimiters, valid placeholder or not
+	reBlock    *regexp.Regexp // block marker as the only text {{#if Key}}, {{else}}, {{/if}}..
+	reRelative *regexp.Regexp // relative key of each block {{.Name}}, {{#if .Insured}}..
+}
+
+var defaultDelims, _ = newDelimiters(DefaultOpenDelimiter, DefaultCloseDelimiter)
+
+// newDelimiters - "{{" and "}}", "[[" and "]]", "«" and "»"..
+func newDelimiters(open, close string) (*delimiters, error) {
+	if open == "" || close == "" {
+		return nil, errors.New("delimiters can't be empty")
+	}
+	if open == close || strings.Contains(open, close) || strings.Contains(close, open) {
+		return nil, errors.New("open and close delimiters must differ")
+	}
+
+	qopen := regexp.QuoteMeta(open)
+	qclose := regexp.QuoteMeta(close)
+
+	// key can't hold delimiter chars too, so it never spans over two placeholders.
+	// Only loop keys start with "#" after dot {{Friends.#number}}
+	keyExclude := `!@#$%^&*()_\-+=\[\]{};:'"\\|<>,?/~…` + classEscape(open+close+escapedOpen)
+
+	// separator never starts with ":=" or ":!=", they are triggers
+	return &delimiters{
+		open:       open,
+		close:      close,
+		reParam:    regexp.MustCompile(qopen + `(#|\?|)((?:[^` + keyExclude + `]|\.#)+?)(| (?:[^\w:][^\w]*?|:[^\w=!][^\w]*?|:))((?:\|(?:"[^"]*"|“[^”]*”))?(?::(?:\w+|!?=)(?:\((?:[^()]|\([^()]*\))*\))?)*)` + qclose),
+		reLike:     regexp.MustCompile(qopen + `[^` + classEscape(open+close) + `]*` + qclose),
+		reBlock:    regexp.MustCompile(`^\s*(` + qopen + `\s*(` + BlockIf + `|` + BlockElse + `|` + BlockIfEnd + `|` + BlockEach + `|` + BlockEachEnd + `)(?:\s+([^` + classEscape(open+close) + `]*?))?\s*` + qclose + `)\s*$`),
+		reRelative: regexp.MustCompile(`(` + qopen + `(?:\s*(?:` + BlockIf + `|` + BlockEach + `)\s+|#|\` + DirectivePrefix + `)?)\.([^` + classEscape(open+close) + `\s:|]*)`),
+	}, nil
+}
+
+// classEscape - escape chars to be used inside regex [...] class
+func classEscape(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`\]^-[`, r) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// wrap - key in delimiters "Key" --> "{{Key}}"
+func (d *delimiters) wrap(s string) string {
+	return d.open + s + d.close
+}
+
+// escape - mark escaped open delimiters: `\{{` --> escapedOpen
+func (d *delimiters) escape(buf []byte) []byte {
+	buf = escapeChars(buf)
+	return bytes.ReplaceAll(buf, []byte(escapeMark+d.open), []byte(escapedOpen))
+}
+
+// escapeChars - escapeChar of text doubled, so it's not taken for escapedOpen
+func escapeChars(buf []byte) []byte {
+	return bytes.ReplaceAll(buf, []byte(escapeChar), []byte(escapeChar+escapeChar))
+}
+
+// unescapeChars - doubled escapeChar back to the one of text, escapedOpen kept
+func unescapeChars(buf []byte) []byte {
+	return bytes.ReplaceAll(buf, []byte(escapeChar+escapeChar), []byte(escapeChar))
+}
+
+// unescape - escaped open delimiters to plain text: escapedOpen --> `{{`,
+// doubled escapeChar to the one of text
+func (d *delimiters) unescape(buf []byte) []byte {
+	if !bytes.Contains(buf, []byte(escapeChar)) {
+		return buf
+	}
+
+	var ret []byte
+	for {
+		i := bytes.Index(buf, []byte(escapeChar))
+		if i < 0 {
+			return append(ret, buf...)
+		}
+		ret = append(ret, buf[:i]...)
+		buf = buf[i+len(escapeChar):]
+
+		switch {
+		case bytes.HasPrefix(buf, []byte(escapeChar)):
+			ret = append(ret, escapeChar...)
+			buf = buf[len(escapeChar):]
+		case bytes.HasPrefix(buf, []byte(escapedOpen[len(escapeChar):])):
+			ret = append(ret, d.open...)
+			buf = buf[len(escapedOpen)-len(escapeChar):]
+		default:
+			ret = append(ret, escapeChar...)
+		}
+	}
+}
@@ -40,11 +40,13 @@ func (DownloadClient) DownloadFile(_ context.Context, urlStr string) (tmpFile st
 		return "", http.ErrMissingFile
 	}
 	// Create file
-	tmpFile = fmt.Sprintf("%x%s", md5.Sum([]byte(urlStr)), path.Ext(urlStr)) // #nosec  G401 - allowed weak hash here
-	out, err := os.Create(tmpFile)                                           // #nosec  G304 - allowed filename variable here
+	// Unique temp file, so parallel downloads of the same url do not collide
+	pattern := fmt.Sprintf("%x-*%s", md5.Sum([]byte(urlStr)), path.Ext(urlStr)) // #nosec  G401 - allowed weak hash here
+	out, err := os.CreateTemp("", pattern)
 	if err != nil {
 		return
 	}
+	tmpFile = out.Name()
 	defer func() {
 		if err := out.Close(); err != nil {
 			log.Printf("download: close: %s", err)
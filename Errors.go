@@ -0,0 +1,155 @@
+package docxplate
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ErrTriggerParts - trigger must be written as {{Key :On:Command:Scope}},
+// more of them one after another {{Key :On:Command:Scope:On:Command:Scope}}
+var ErrTriggerParts = errors.New("trigger must have exactly 3 parts :on:command:scope")
+
+// ErrCompiledDelimiters - compiled template parts are parsed with delimiters
+// of template, WithDelimiters can't be given to CompiledTemplate.Render
+var ErrCompiledDelimiters = errors.New("delimiters of compiled template can't be changed on render")
+
+// PartError - template part (document, header1, footer2..) can't be read or parsed
+type PartError struct {
+	Part string
+	Err  error
+}
+
+func (e *PartError) Error() string {
+	return fmt.Sprintf("part [%s]: %s", e.Part, e.Err)
+}
+
+func (e *PartError) Unwrap() error {
+	return e.Err
+}
+
+// ParamsError - given params can't be decoded (e.g. invalid JSON)
+type ParamsError struct {
+	Err error
+}
+
+func (e *ParamsError) Error() string {
+	return fmt.Sprintf("params: %s", e.Err)
+}
+
+func (e *ParamsError) Unwrap() error {
+	return e.Err
+}
+
+// ValueError - DocxValue of param value failed, param is left out
+type ValueError struct {
+	Key string
+	Err error
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("param [%s]: %s", e.Key, e.Err)
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+// ImageError - image of placeholder can't be added to document
+type ImageError struct {
+	Part  string
+	Key   string
+	Image *Image
+	Err   error
+}
+
+func (e *ImageError) Error() string {
+	src := ""
+	if e.Image != nil {
+		src = e.Image.Path
+		if src == "" {
+			src = e.Image.URL
+		}
+	}
+	return fmt.Sprintf("part [%s]: image [%s] (%s): %s", e.Part, e.Key, src, e.Err)
+}
+
+func (e *ImageError) Unwrap() error {
+	return e.Err
+}
+
+// TriggerError - placeholder holds invalid trigger
+type TriggerError struct {
+	Part    string
+	Key     string
+	Trigger string // raw trigger as in template ":empty:remove"
+	Err     error
+}
+
+func (e *TriggerError) Error() string {
+	return fmt.Sprintf("part [%s]: placeholder [%s] trigger [%s]: %s", e.Part, e.Key, e.Trigger, e.Err)
+}
+
+func (e *TriggerError) Unwrap() error {
+	return e.Err
+}
+
+// BlockError - block markers {{#if Key}}, {{else}}, {{/if}} are not paired,
+// block is left as it is. Or key of {{#each Key}} is not a slice, block is removed
+type BlockError struct {
+	Part   string
+	Marker string // as in template "{{#if Insurance}}"
+	Key    string // key of #if and #each
+	Err    error
+}
+
+func (e *BlockError) Error() string {
+	return fmt.Sprintf("part [%s]: block [%s]: %s", e.Part, e.Marker, e.Err)
+}
+
+func (e *BlockError) Unwrap() error {
+	return e.Err
+}
+
+// FormatError - placeholder value can't be formatted, it's left as is
+type FormatError struct {
+	Part   string
+	Key    string
+	Format string // ":date(long)"
+	Err    error
+}
+
+func (e *FormatError) Error() string {
+	return fmt.Sprintf("part [%s]: placeholder [%s] format [%s]: %s", e.Part, e.Key, e.Format, e.Err)
+}
+
+func (e *FormatError) Unwrap() error {
+	return e.Err
+}
+
+// UnresolvedPlaceholder - placeholder left without value after render
+type UnresolvedPlaceholder struct {
+	Placeholder string // {{Name :upper}}
+	Key         string // Name
+	Location
+}
+
+// UnresolvedError - placeholders left without value (see MissingKeyFail)
+type UnresolvedError struct {
+	Placeholders []UnresolvedPlaceholder
+}
+
+func (e *UnresolvedError) Error() string {
+	var list []string
+	for _, up := range e.Placeholders {
+		list = append(list, fmt.Sprintf("%s (%s)", up.Placeholder, up.Location))
+	}
+	return fmt.Sprintf("%d unresolved placeholders: %s", len(e.Placeholders), strings.Join(list, ", "))
+}
+
+// partName - short name of template file used in errors
+// "word/document.xml" --> "document", "word/header1.xml" --> "header1"
+func partName(fname string) string {
+	return strings.TrimSuffix(path.Base(fname), path.Ext(fname))
+}
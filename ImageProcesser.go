@@ -13,10 +13,11 @@ import (
 var imgXMLTpl = "<w:pict><v:shape style='width:%dpt;height:%dpt'><v:imagedata r:id='%s'/></v:shape></w:pict>"
 
 // Process image placeholder - add file, rels and return replace val
-func processImage(img *Image) (imgXMLStr string, err error) {
+func (t *Template) processImage(img *Image) (imgXMLStr string, err error) {
 	var imgPath string
 
 	imgPath = img.Path // default
+	mediaName := imgPath
 	if img.Path == "" {
 		imgPath, err = DefaultDownloader.DownloadFile(context.Background(), img.URL)
 		if err != nil {
@@ -28,6 +29,9 @@ func processImage(img *Image) (imgXMLStr string, err error) {
 				log.Printf("image process: remove: %s", err)
 			}
 		}()
+
+		// downloaded file is a temp file somewhere outside of working dir
+		mediaName = path.Base(imgPath)
 	}
 
 	// Add image to zip
@@ -35,7 +39,7 @@ func processImage(img *Image) (imgXMLStr string, err error) {
 	if err != nil {
 		return
 	}
-	t.added["word/media/"+imgPath] = imgBytes
+	t.added["word/media/"+mediaName] = imgBytes
 
 	// Add image content type
 	var isContainType bool
@@ -43,9 +47,12 @@ func processImage(img *Image) (imgXMLStr string, err error) {
 	contentTypesName := "[Content_Types].xml"
 	var contentTypesNode *xmlNode
 	if contentTypesBytes, ok := t.modified[contentTypesName]; ok {
-		contentTypesNode = t.bytesToXMLStruct(contentTypesBytes)
+		contentTypesNode, err = t.bytesToXMLStruct(contentTypesBytes)
 	} else {
-		contentTypesNode = t.fileToXMLStruct(contentTypesName)
+		contentTypesNode, err = t.fileToXMLStruct(contentTypesName)
+	}
+	if err != nil {
+		return
 	}
 	contentTypesNode.childFirst.iterate(func(node *xmlNode) bool {
 		if strings.ToLower(node.Attr("Extension")) == imgExt {
@@ -74,9 +81,12 @@ func processImage(img *Image) (imgXMLStr string, err error) {
 	var relNode *xmlNode
 	relName := "word/_rels/document.xml.rels"
 	if relNodeBytes, ok := t.modified[relName]; ok {
-		relNode = t.bytesToXMLStruct(relNodeBytes)
+		relNode, err = t.bytesToXMLStruct(relNodeBytes)
 	} else {
-		relNode = t.fileToXMLStruct(relName)
+		relNode, err = t.fileToXMLStruct(relName)
+	}
+	if err != nil {
+		return
 	}
 	rid := fmt.Sprintf("rId%d", relNode.childLenght+1)
 	relNode.addSub(&xmlNode{
@@ -87,7 +97,7 @@ func processImage(img *Image) (imgXMLStr string, err error) {
 		Attrs: []xml.Attr{
 			{Name: xml.Name{Space: "", Local: "Id"}, Value: rid},
 			{Name: xml.Name{Space: "", Local: "Type"}, Value: "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"},
-			{Name: xml.Name{Space: "", Local: "Target"}, Value: "media/" + imgPath},
+			{Name: xml.Name{Space: "", Local: "Target"}, Value: "media/" + mediaName},
 		},
 		parent: relNode,
 		isNew:  true,
@@ -99,3 +109,20 @@ func processImage(img *Image) (imgXMLStr string, err error) {
 
 	return
 }
+
+// prepareImageParam - image params are processed (added to this template
+// files) only when their placeholder is found, so param value is set here
+func (t *Template) prepareImageParam(p *Param) error {
+	if p.image == nil {
+		return nil
+	}
+
+	imgXMLStr, err := t.processImage(p.image)
+	if err != nil {
+		return err
+	}
+	p.Value = imgXMLStr
+	p.image = nil // processed, reuse value
+
+	return nil
+}
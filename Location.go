@@ -0,0 +1,71 @@
+package docxplate
+
+import "fmt"
+
+// LocationKind - kind of element holding a placeholder
+type LocationKind int8
+
+// Location kinds
+const (
+	LocationParagraph LocationKind = iota // plain paragraph
+	LocationListItem                      // list item paragraph
+	LocationTableCell                     // paragraph inside table cell (table row)
+	LocationAttribute                     // element attribute (e.g. watermark text)
+)
+
+// String - kind as human readable text
+func (kind LocationKind) String() string {
+	switch kind {
+	case LocationListItem:
+		return "list item"
+	case LocationTableCell:
+		return "table cell"
+	case LocationAttribute:
+		return "attribute"
+	default:
+		return "paragraph"
+	}
+}
+
+// Location - where in template a placeholder is
+type Location struct {
+	Part string // document, header1, footer2..
+	Kind LocationKind
+	Text string // plaintext of paragraph (or attribute value) holding placeholder
+}
+
+// String - "document: table cell "Hello {{Name}}""
+func (loc Location) String() string {
+	return fmt.Sprintf("%s: %s %q", loc.Part, loc.Kind, loc.Text)
+}
+
+// locate - location of text node inside given part
+func (t *Template) locate(part string, xnode *xmlNode) Location {
+	loc := Location{
+		Part: part,
+		Kind: LocationParagraph,
+	}
+
+	var nrow *xmlNode
+	for n := xnode; n != nil; n = n.parent {
+		switch n.Tag() {
+		case "w-p":
+			if nrow != nil {
+				continue
+			}
+			nrow = n
+			loc.Text = string(t.delimiters().unescape(n.AllContents()))
+			if isListItem, _ := n.IsListItem(); isListItem {
+				loc.Kind = LocationListItem
+			}
+		case "w-tc":
+			loc.Kind = LocationTableCell
+			return loc
+		}
+	}
+
+	if nrow == nil {
+		loc.Text = string(t.delimiters().unescape(xnode.AllContents()))
+	}
+	return loc
+}
@@ -0,0 +1,86 @@
+package docxplate
+
+import (
+	"fmt"
+	"slices"
+
+	"golang.org/x/text/language"
+)
+
+// Option - template or render option.
+// Options given when opening template are used for every render,
+// options given to Render only for that render
+//
+//	tdoc, _ := docxplate.OpenTemplate("template.docx", docxplate.WithDelimiters("[[", "]]"))
+//	tdoc.Render(user, docxplate.WithMissingKey(docxplate.MissingKeyFail))
+type Option func(*Template)
+
+// MissingKey - what to do with placeholders left without value after render
+type MissingKey int8
+
+// Missing key policies
+const (
+	MissingKeyKeep  MissingKey = iota // leave placeholder as is (default)
+	MissingKeyBlank                   // remove placeholder from document
+	MissingKeyFail                    // leave placeholder and return *UnresolvedError listing all of them
+)
+
+// WithMissingKey - set missing key policy
+func WithMissingKey(policy MissingKey) Option {
+	return func(t *Template) {
+		t.missingKey = policy
+	}
+}
+
+// WithStrictEmpty - :empty trigger fires only on empty value, not on unknown
+// or null keys as it does by default. Use :unknown and :null for those
+func WithStrictEmpty() Option {
+	return func(t *Template) {
+		t.strictEmpty = true
+	}
+}
+
+// WithDelimiters - placeholder delimiters instead of default "{{" and "}}"
+// e.g. "[[" and "]]" or "«" and "»" (Word MERGEFIELD style).
+// Text in other delimiters is left as is.
+// Give it when opening template, as it's used to parse template files.
+// Template.Render takes it for that render only, CompiledTemplate.Render
+// returns ErrCompiledDelimiters as its files are parsed already
+func WithDelimiters(open, close string) Option {
+	return func(t *Template) {
+		d, err := newDelimiters(open, close)
+		if err != nil {
+			t.optionErr = err
+			return
+		}
+		t.delims = d
+	}
+}
+
+// WithLocale - locale of :number, :currency and :percent formats,
+// e.g. "lv" or "en-US". Placeholder can have its own: {{Total :number(2, de)}}
+func WithLocale(locale string) Option {
+	return func(t *Template) {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			t.optionErr = fmt.Errorf("locale [%s]: %w", locale, err)
+			return
+		}
+		t.locale = tag
+	}
+}
+
+// apply template options and then render options on top of defaults
+func (t *Template) applyOptions(opts []Option) error {
+	t.delims = defaultDelims
+	t.missingKey = MissingKeyKeep
+	t.locale = language.Und
+	t.strictEmpty = false
+	t.optionErr = nil
+
+	for _, opt := range slices.Concat(t.opts, opts) {
+		opt(t)
+	}
+
+	return t.optionErr
+}
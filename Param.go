@@ -3,14 +3,14 @@ package docxplate
 import (
 	"bytes"
 	"fmt"
-	"regexp"
+	"reflect"
 	"strings"
 )
 
 // ParamPattern - regex pattern to identify params
 // const ParamPattern = `{{(#|)([\w\.]+?)(| .*?)(| [:a-z]+?)}}`
 // var reParamExtract = regexp.MustCompile(`{{(#|)([\w\.\ \-]+?)(| [^\w]+?)(|(:[\w]+){1,3}?)}}`)
-var reParamExtract = regexp.MustCompile(`\{\{(#|)([^!@#$%^&*()_\-+=\[\]{};:'"\\|<>,?/~…]+?)(| [^\w]+?)(|(:[\w]+){1,3}?)}}`)
+// Regex is built for template delimiters, see newDelimiters
 
 // ParamType ..
 type ParamType int8
@@ -35,10 +35,14 @@ type Image struct {
 // Param ..
 type Param struct {
 	Key   string
-	Value string
+	Value string // value as text in document
 	Type  ParamType
 	Level int
 
+	// TypedValue - original value Value is made of: time.Time, float64,
+	// bool, json.Number (numbers from JSON).. *Image of ImageParam
+	TypedValue any
+
 	Params ParamList
 
 	parent *Param
@@ -48,12 +52,24 @@ type Param struct {
 
 	Separator string // {{Usernames SEPERATOR}}
 	VMerge    bool   // {{Name :vmerge}}
+	Directive bool   // {{?Name :remove:row}}, see DirectivePrefix
 
-	Trigger   *ParamTrigger
+	Trigger   *ParamTrigger   // first of Triggers
+	Triggers  []*ParamTrigger // {{Name :empty:clear:cell:unknown:remove:row}}, first fired one is run
 	Formatter *ParamFormatter
 
+	modifiers string // params part as written ":date(long):empty:remove:row"
+
 	RowPlaceholder string
 	Index          int // slice data index,expandPlaceholders function needs
+
+	image *Image // unprocessed image of ImageParam
+	err   error  // value can't be collected, see DocxValuer
+
+	null    bool // key with null value, see State
+	unknown bool // no such key (placeholder left without param)
+
+	delims *delimiters // template placeholder delimiters, default if nil
 }
 
 // NewParam ..
@@ -69,8 +85,13 @@ func NewParam(key any) *Param {
 
 // NewParamFromRaw ..
 func NewParamFromRaw(raw []byte) *Param {
+	return newParamFromRaw(raw, defaultDelims)
+}
+
+// newParamFromRaw - NewParamFromRaw for given delimiters
+func newParamFromRaw(raw []byte, d *delimiters) *Param {
 	// extract from raw contents
-	matches := reParamExtract.FindAllSubmatch(raw, -1)
+	matches := d.reParam.FindAllSubmatch(raw, -1)
 	if matches == nil || matches[0] == nil {
 		return nil
 	}
@@ -87,10 +108,14 @@ func NewParamFromRaw(raw []byte) *Param {
 	// }
 
 	p := NewParam(string(matches[0][2]))
+	p.delims = d
 	p.Separator = strings.TrimSpace(string(matches[0][3]))
 	p.VMerge = isVMergeMark(matches[0][4])
-	p.Trigger = NewParamTrigger(matches[0][4])
+	p.Directive = string(matches[0][1]) == DirectivePrefix
+	trs, _ := placeholderTriggers(p.Directive, matches[0][4])
+	p.setTriggers(trs)
 	p.Formatter = NewFormatter(matches[0][4])
+	p.modifiers = strings.TrimSpace(string(matches[0][4]))
 
 	// fmt.Printf("[%s]\n", p.String())
 	return p
@@ -99,13 +124,18 @@ func NewParamFromRaw(raw []byte) *Param {
 // string placeholder replace
 func (p *Param) replaceIn(buf []byte) []byte {
 	// log.Printf("REPALCEEEEE: [%v][%s]", p.Placeholder(), p.Value)
-	buf = bytes.ReplaceAll(buf, []byte(p.Placeholder()), []byte(p.Value))
+	buf = bytes.ReplaceAll(buf, []byte(p.Placeholder()), escapeChars([]byte(p.Value)))
 	buf = bytes.ReplaceAll(buf, []byte(p.PlaceholderKey()), []byte(p.Key))
 	return buf
 }
 
-// SetValue - any value to string
+// SetValue - keep typed value and its string form
 func (p *Param) SetValue(val any) {
+	if rv, ok := val.(reflect.Value); ok {
+		val = reflectValueOf(rv)
+	}
+	p.TypedValue = val
+
 	switch v := val.(type) {
 	case string:
 		p.Value = v
@@ -115,9 +145,39 @@ func (p *Param) SetValue(val any) {
 
 }
 
+// reflectValueOf - value of reflect, basic kinds even from unexported
+// embedded structs (which can't be taken as interface)
+func reflectValueOf(rv reflect.Value) any {
+	if rv.CanInterface() {
+		return rv.Interface()
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	}
+	return fmt.Sprintf("%v", rv)
+}
+
 // paramsSuffix - " formatter trigger vmerge" part of a placeholder,
-// or "" when the param has none of them
+// or "" when the param has none of them.
+// Params part as written in template is used when known
 func (p *Param) paramsSuffix() string {
+	if strings.HasPrefix(p.modifiers, defaultMark) {
+		// quoted default follows key without space {{Phone|"n/a"}}
+		return p.modifiers
+	}
+	if p.modifiers != "" {
+		return " " + p.modifiers
+	}
 	if p.Formatter == nil && p.Trigger == nil && !p.VMerge {
 		return ""
 	}
@@ -127,7 +187,7 @@ func (p *Param) paramsSuffix() string {
 		formatter = p.Formatter.String()
 	}
 	if p.Trigger != nil {
-		trigger = p.Trigger.String()
+		trigger = triggersString(p.Triggers)
 	}
 	if p.VMerge {
 		vmerge = ParamVMerge
@@ -135,34 +195,42 @@ func (p *Param) paramsSuffix() string {
 	return " " + formatter + trigger + vmerge
 }
 
+// delimiters of param placeholders
+func (p *Param) delimiters() *delimiters {
+	if p.delims == nil {
+		return defaultDelims
+	}
+	return p.delims
+}
+
 // Placeholder .. {{Key}}
 func (p *Param) Placeholder() string {
-	return "{{" + p.AbsoluteKey + p.paramsSuffix() + "}}"
+	return p.delimiters().wrap(p.AbsoluteKey + p.paramsSuffix())
 }
 
 // PlaceholderKey .. {{#Key}}
 func (p *Param) PlaceholderKey() string {
-	return "{{#" + p.AbsoluteKey + p.paramsSuffix() + "}}"
+	return p.delimiters().wrap("#" + p.AbsoluteKey + p.paramsSuffix())
 }
 
 // PlaceholderInline .. {{Key ,}}
 func (p *Param) PlaceholderInline() string {
-	return "{{" + p.AbsoluteKey + " " // "{{Key " - space suffix
+	return p.delimiters().open + p.AbsoluteKey + " " // "{{Key " - space suffix
 }
 
 // PlaceholderKeyInline .. {{#Key ,}}
 func (p *Param) PlaceholderKeyInline() string {
-	return "{{#" + p.AbsoluteKey + " " // "{{#Key " - space suffix
+	return p.delimiters().open + "#" + p.AbsoluteKey + " " // "{{#Key " - space suffix
 }
 
 // PlaceholderPrefix .. {{Key
 func (p *Param) PlaceholderPrefix() string {
-	return "{{" + p.AbsoluteKey // "{{Key"
+	return p.delimiters().open + p.AbsoluteKey // "{{Key"
 }
 
 // PlaceholderKeyPrefix .. {{#Key
 func (p *Param) PlaceholderKeyPrefix() string {
-	return "{{#" + p.AbsoluteKey // "{{#Key"
+	return p.delimiters().open + "#" + p.AbsoluteKey // "{{#Key"
 }
 
 // ToCompact - convert AbsoluteKey placeholder to ComplexKey placeholder
@@ -226,6 +294,8 @@ func (p *Param) rawParamsFrom(buf []byte) (raw []byte, ok bool) {
 	prefixes := []string{
 		p.PlaceholderInline(),
 		p.PlaceholderKeyInline(),
+		p.PlaceholderPrefix() + defaultMark,
+		p.PlaceholderKeyPrefix() + defaultMark,
 	}
 	for _, pref := range prefixes {
 		bpref := []byte(pref)
@@ -233,11 +303,15 @@ func (p *Param) rawParamsFrom(buf []byte) (raw []byte, ok bool) {
 			continue
 		}
 
-		// Get part where params are (remove placeholder prefix)
+		// Get part where params are (remove placeholder prefix),
+		// quoted default mark is part of params {{Phone|"n/a"}}
 		raw = bytes.SplitN(buf, bpref, 2)[1]
+		if strings.HasSuffix(pref, defaultMark) {
+			raw = append([]byte(defaultMark), raw...)
+		}
 
 		// Remove placeholder suffix and only raw params part left
-		raw = bytes.SplitN(raw, []byte("}}"), 2)[0]
+		raw = bytes.SplitN(raw, []byte(p.delimiters().close), 2)[0]
 
 		return raw, true
 	}
@@ -245,16 +319,31 @@ func (p *Param) rawParamsFrom(buf []byte) (raw []byte, ok bool) {
 	return nil, false
 }
 
+// Take params part as written in raw contents specific to this param
+func (p *Param) extractModifiers(buf []byte) {
+	raw, _ := p.rawParamsFrom(buf)
+	p.modifiers = strings.TrimSpace(string(raw))
+}
+
 // Try to extract trigger from raw contents specific to this param
 func (p *Param) extractTriggerFrom(buf []byte) *ParamTrigger {
 	raw, ok := p.rawParamsFrom(buf)
 	if !ok {
 		return nil
 	}
-	p.Trigger = NewParamTrigger(raw)
+	p.setTriggers(NewParamTriggers(raw))
 	return p.Trigger
 }
 
+// setTriggers - triggers of placeholder, the first one is Trigger
+func (p *Param) setTriggers(trs []*ParamTrigger) {
+	p.Triggers = trs
+	p.Trigger = nil
+	if len(trs) > 0 {
+		p.Trigger = trs[0]
+	}
+}
+
 // Try to extract formatter from raw contents specific to this param
 func (p *Param) extractFormatter(buf []byte) *ParamFormatter {
 	raw, ok := p.rawParamsFrom(buf)
@@ -276,19 +365,31 @@ func (p *Param) extractVMerge(buf []byte) bool {
 	return isVMergeMark(raw)
 }
 
-// RunTrigger - execute trigger
+// RunTrigger - execute the first trigger which fires, in written order.
+// Others are skipped as the first one already took care of placeholder
 func (p *Param) RunTrigger(xnode *xmlNode) {
-	if p == nil || p.Trigger == nil {
+	p.runTriggers(xnode, triggerEnv{})
+}
+
+// runTriggers - RunTrigger with params and settings of template
+func (p *Param) runTriggers(xnode *xmlNode, env triggerEnv) {
+	if p == nil {
 		return
 	}
 
-	if p.Trigger.On == TriggerOnEmpty && p.Value != "" {
-		return
+	for _, tr := range p.Triggers {
+		if tr.fires(p, env) {
+			p.runTrigger(tr, xnode)
+			return
+		}
 	}
+}
 
+// runTrigger - execute command of trigger on its scope
+func (p *Param) runTrigger(tr *ParamTrigger, xnode *xmlNode) {
 	// 1. Scope - find affected node
 	var ntypes = NodeSingleTypes
-	switch p.Trigger.Scope {
+	switch tr.Scope {
 	case TriggerScopeCell:
 		ntypes = NodeCellTypes
 	case TriggerScopeRow:
@@ -299,6 +400,8 @@ func (p *Param) RunTrigger(xnode *xmlNode) {
 		ntypes = []string{"w-tbl"}
 	case TriggerScopeSection:
 		ntypes = NodeSectionTypes
+	case TriggerScopeParagraph:
+		ntypes = []string{"w-p"}
 	}
 
 	n := xnode.closestUp(ntypes)
@@ -309,8 +412,8 @@ func (p *Param) RunTrigger(xnode *xmlNode) {
 	isListItem, listID := n.IsListItem()
 
 	// Whole lists: special case
-	isListRemove := p.Trigger.Scope == TriggerScopeList                                   // :list
-	isListRemove = isListRemove || (isListItem && p.Trigger.Scope == TriggerScopeSection) // :section
+	isListRemove := tr.Scope == TriggerScopeList                                   // :list
+	isListRemove = isListRemove || (isListItem && tr.Scope == TriggerScopeSection) // :section
 	if isListRemove && isListItem {
 		// find all list items as this
 		n.parent.childFirst.iterate(func(wpNode *xmlNode) bool {
@@ -318,7 +421,7 @@ func (p *Param) RunTrigger(xnode *xmlNode) {
 			if !isitem || listid != listID {
 				return false
 			}
-			if p.Trigger.Command == TriggerCommandRemove {
+			if tr.Command == TriggerCommandRemove {
 				wpNode.delete()
 			}
 			return false
@@ -327,12 +430,12 @@ func (p *Param) RunTrigger(xnode *xmlNode) {
 	}
 
 	// Simple cases
-	if p.Trigger.Command == TriggerCommandRemove {
+	if tr.Command == TriggerCommandRemove {
 		n.delete()
 		return
 	}
 
-	if p.Trigger.Command == TriggerCommandClear {
+	if tr.Command == TriggerCommandClear {
 		n.Content = nil
 		n.Walk(func(n2 *xmlNode) {
 			n2.Content = nil
@@ -346,6 +449,6 @@ func (p *Param) String() string {
 	s := fmt.Sprintf("%34s=%-20s", p.AbsoluteKey, p.Value)
 	s += fmt.Sprintf("\tSeparator[%s]", p.Separator)
 	s += fmt.Sprintf("\tFormatter[%s]", p.Formatter)
-	s += fmt.Sprintf("\tTrigger[%s]", p.Trigger)
+	s += fmt.Sprintf("\tTrigger[%s]", triggersString(p.Triggers))
 	return s
 }
@@ -0,0 +1,80 @@
+package docxplate
+
+import "strings"
+
+// ParamDefault - placeholder text when value is empty or key is missing
+// {{Phone :default(n/a)}} or {{Phone|"not provided"}}
+const ParamDefault = ":default"
+
+// defaultMark - start of quoted default right after key {{Phone|"not provided"}}
+const defaultMark = "|"
+
+// default text quotes, straight and the ones Word turns them into
+var defaultQuotes = [][2]string{{`"`, `"`}, {"“", "”"}}
+
+// quotedDefault - default text of raw params starting with |"text",
+// and rest of raw params after it
+func quotedDefault(raw string) (text, rest string, ok bool) {
+	if !strings.HasPrefix(raw, defaultMark) {
+		return "", raw, false
+	}
+	raw = raw[len(defaultMark):]
+
+	for _, q := range defaultQuotes {
+		if !strings.HasPrefix(raw, q[0]) {
+			continue
+		}
+		raw = raw[len(q[0]):]
+		end := strings.Index(raw, q[1])
+		if end < 0 {
+			return raw, "", true
+		}
+		return raw[:end], raw[end+len(q[1]):], true
+	}
+	return "", raw, false
+}
+
+// defaultValue - default text of raw params part (after param key)
+func defaultValue(raw []byte) (string, bool) {
+	mods := splitModifiers(string(raw))
+	if mods.def == nil {
+		return "", false
+	}
+	return mods.def.args, true
+}
+
+// Try to extract default text from raw contents specific to this param
+func (p *Param) extractDefault(buf []byte) (string, bool) {
+	raw, ok := p.rawParamsFrom(buf)
+	if !ok {
+		return "", false
+	}
+	return defaultValue(raw)
+}
+
+// Placeholders left without value but with default text get it
+func (t *Template) replaceDefaults(xnode *xmlNode) {
+	if t.params == nil {
+		return
+	}
+
+	d := t.delimiters()
+	replace := func(buf []byte) []byte {
+		return d.reParam.ReplaceAllFunc(buf, func(placeholder []byte) []byte {
+			text, ok := defaultValue(d.reParam.FindSubmatch(placeholder)[4])
+			if !ok {
+				return placeholder
+			}
+			return []byte(text)
+		})
+	}
+
+	xnode.Walk(func(n *xmlNode) {
+		for i := range n.Attrs {
+			n.Attrs[i].Value = string(replace([]byte(n.Attrs[i].Value)))
+		}
+		if len(n.Content) > 0 {
+			n.Content = replace(n.Content)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package docxplate
+
+import "bytes"
+
+// DirectivePrefix - key prefix of directive: placeholder which leaves no text,
+// only runs its trigger {{?HasDiscount :remove:paragraph}}
+const DirectivePrefix = "?"
+
+// parseDirectiveTriggers - triggers of directive. Command and scope
+// alone keep content only if key is true: {{?IsVIP :remove:row}} is {{?IsVIP :if:remove:row}}
+func parseDirectiveTriggers(raw []byte) ([]*ParamTrigger, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(splitModifiers(string(raw)).trigger) == 2 {
+		raw = append([]byte(TriggerOnIf), raw...)
+	}
+	return parseParamTriggers(raw)
+}
+
+// placeholderTriggers - triggers of placeholder or directive raw params part
+func placeholderTriggers(directive bool, raw []byte) ([]*ParamTrigger, error) {
+	if directive {
+		return parseDirectiveTriggers(raw)
+	}
+	return parseParamTriggers(raw)
+}
+
+// Directives run their triggers on value of their key in params and leave no text
+func (t *Template) runDirectives(xnode *xmlNode, params ParamList) {
+	if params == nil {
+		return
+	}
+
+	d := t.delimiters()
+	xnode.Walk(func(n *xmlNode) {
+		if len(n.Content) == 0 {
+			return
+		}
+		for _, match := range d.reParam.FindAllSubmatch(n.Content, -1) {
+			if string(match[1]) != DirectivePrefix {
+				continue
+			}
+			n.Content = bytes.Replace(n.Content, match[0], nil, 1)
+			t.directiveParam(match[0], params).runTriggers(n, t.triggerEnv(params))
+		}
+	})
+}
+
+// directiveParam - param of directive key with triggers of directive
+func (t *Template) directiveParam(raw []byte, params ParamList) *Param {
+	p := newParamFromRaw(raw, t.delimiters())
+
+	found := params.lookupKey("", p.AbsoluteKey)
+	if found == nil {
+		switch params.keyState(p.AbsoluteKey) {
+		case ParamStateUnknown:
+			p.unknown = true
+		case ParamStateNull:
+			p.null = true
+		}
+		return p
+	}
+
+	dp := *found
+	dp.setTriggers(p.Triggers)
+	return &dp
+}
@@ -0,0 +1,101 @@
+package docxplate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Date format presets: {{SignedAt :date(long)}}
+var datePresets = map[string]string{
+	"short": "2006-01-02",
+	"long":  "January 2, 2006",
+	"iso":   time.RFC3339,
+}
+
+// layouts of date texts (from JSON, maps..) date format accepts
+var dateTextLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// formatDate - :date(layout, time zone) of time.Time or date text.
+// Empty value stays empty
+func formatDate(typed any, text, args string) (string, error) {
+	layout, loc, err := dateArgs(args)
+	if err != nil {
+		return text, err
+	}
+
+	var tm time.Time
+	switch v := typed.(type) {
+	case time.Time:
+		tm = v
+	case *time.Time:
+		if v == nil {
+			return "", nil
+		}
+		tm = *v
+	default:
+		if text == "" {
+			return "", nil
+		}
+		if tm, err = parseDateText(text); err != nil {
+			return text, err
+		}
+	}
+
+	if loc != nil {
+		tm = tm.In(loc)
+	}
+	return tm.Format(layout), nil
+}
+
+// parseDateText - RFC 3339 and alike date text to time
+func parseDateText(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	for _, layout := range dateTextLayouts {
+		if tm, err := time.Parse(layout, text); err == nil {
+			return tm, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("not a date [%s]", text)
+}
+
+// dateArgs - layout and time zone of date format arguments:
+// (2006-01-02), (long), (Europe/Riga), (Jan 2, 2006 15:04, UTC)
+func dateArgs(args string) (layout string, loc *time.Location, err error) {
+	layout = strings.TrimSpace(args)
+
+	var zone string
+	if i := strings.LastIndex(layout, ","); i >= 0 && isZoneName(strings.TrimSpace(layout[i+1:])) {
+		layout, zone = strings.TrimSpace(layout[:i]), strings.TrimSpace(layout[i+1:])
+	} else if isZoneName(layout) {
+		layout, zone = "", layout
+	}
+
+	if preset, ok := datePresets[strings.ToLower(layout)]; ok {
+		layout = preset
+	}
+	if layout == "" {
+		layout = datePresets["short"]
+	}
+
+	if zone != "" {
+		if loc, err = time.LoadLocation(zone); err != nil {
+			return "", nil, fmt.Errorf("time zone: %w", err)
+		}
+	}
+	return layout, loc, nil
+}
+
+// reZoneName - IANA time zone name "Europe/Riga", "America/Argentina/Salta", "Etc/GMT+2"
+var reZoneName = regexp.MustCompile(`^[A-Z][A-Za-z_]+/[A-Za-z][A-Za-z_/+\-0-9]*$`)
+
+// isZoneName - "UTC", "Local", "Europe/Riga".. not a part of layout like "01/02"
+func isZoneName(s string) bool {
+	return s == "UTC" || s == "Local" || reZoneName.MatchString(s)
+}
@@ -2,6 +2,9 @@ package docxplate
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"golang.org/x/text/cases"
@@ -14,45 +17,186 @@ const (
 	FormatUpper      = ":upper"
 	FormatTitle      = ":title"
 	FormatCapitalize = ":capitalize"
+	FormatTrim       = ":trim"
+	FormatTruncate   = ":truncate" // :truncate(max chars, suffix)
+	FormatDate       = ":date"     // :date(layout or preset, time zone)
+	FormatNumber     = ":number"   // :number(decimals, locale)
+	FormatCurrency   = ":currency" // :currency(code, locale)
+	FormatPercent    = ":percent"  // :percent(decimals, locale)
 )
 
-// ParamFormatter ..
+// all known formats
+var formats = []string{
+	FormatLower, FormatUpper, FormatTitle, FormatCapitalize, FormatTrim, FormatTruncate,
+	FormatDate, FormatNumber, FormatCurrency, FormatPercent,
+}
+
+// isFormatWord - is "upper", "lower".. a built-in format
+func isFormatWord(word string) bool {
+	return inSlice(":"+word, formats)
+}
+
+// formatEnv - template settings formats depend on
+type formatEnv struct {
+	locale     language.Tag          // of number formats
+	formatters map[string]FormatFunc // custom formatters of template
+}
+
+// ParamFormatter - formats of placeholder applied left to right
+// {{Name :trim:truncate(20):upper}} --> trim, truncate(20), upper
 type ParamFormatter struct {
 	raw    string
+	Steps  []FormatStep
+	Format string   // last format of steps
+	Args   []string // arguments of last format
+}
+
+// FormatStep - single format of formatter with its arguments
+type FormatStep struct {
 	Format string
+	Args   []string // :date(long, Europe/Riga) --> [long Europe/Riga]
+
+	args string // arguments as written
 }
 
-// NewFormatter - take raw ":empty:remove:list" and make formatter and its fields from it
+// NewFormatter - take raw ":upper:empty:remove:list" or ":trim:date(long)" and make formatter and its fields from it
 func NewFormatter(raw []byte) *ParamFormatter {
 	raw = bytes.TrimSpace(raw)
-	raw = bytes.ToLower(raw)
 
 	// init with defaults
 	f := &ParamFormatter{
 		raw: string(raw),
 	}
 
-	// Always must start with ":"
-	if !strings.HasPrefix(f.raw, ":") {
+	// Always must start with ":" (or default)
+	if !hasModifiers(f.raw) {
 		return nil
 	}
 
-	// Remove the first ":" so split parts counting is more readable
-	// Split into parts
-	parts := strings.Split(f.raw[1:], ":")
+	for _, m := range splitModifiers(f.raw).formats {
+		f.Steps = append(f.Steps, FormatStep{
+			Format: ":" + m.name,
+			Args:   m.argList(),
+			args:   m.args,
+		})
+	}
 
-	for _, part := range parts {
-		switch part {
-		case "lower", "upper", "title", "capitalize":
-			f.Format = ":" + part
-		}
+	if len(f.Steps) > 0 {
+		last := f.Steps[len(f.Steps)-1]
+		f.Format, f.Args = last.Format, last.Args
 	}
 
 	return f
 }
 
-// ApplyFormat - apply formatting to the given content based on the formatter
+// format - formatted param value, steps applied left to right.
+// First step gets typed value of param, next steps text of previous one
+func (p *ParamFormatter) format(param *Param, env formatEnv) (string, error) {
+	typed, text := param.TypedValue, param.Value
+	for _, step := range p.Steps {
+		var err error
+		if text, err = step.format(typed, text, env); err != nil {
+			return param.Value, fmt.Errorf("%s: %w", step, err)
+		}
+		typed = text
+	}
+	return text, nil
+}
+
+// format - formatted value. Formats needing real value
+// (dates, numbers, custom..) use typed value, others work on text.
+// Unknown format leaves text as is (reported by Template.Lint)
+func (step FormatStep) format(typed any, text string, env formatEnv) (string, error) {
+	switch step.Format {
+	case FormatDate:
+		return formatDate(typed, text, step.args)
+	case FormatNumber, FormatCurrency, FormatPercent:
+		return formatNumber(step.Format, typed, text, step.Args, env.locale)
+	case FormatTruncate:
+		return truncate(text, step.Args)
+	}
+
+	if isFormatWord(step.Format[1:]) {
+		return string(applyFormat(step.Format, []byte(text))), nil
+	}
+	if fn, ok := customFormatter(step.Format, env.formatters); ok {
+		return fn(typed, step.Args)
+	}
+	return text, nil
+}
+
+// known - is format built-in or custom one of template or all templates
+func (step FormatStep) known(formatters map[string]FormatFunc) bool {
+	if isFormatWord(step.Format[1:]) {
+		return true
+	}
+	_, ok := customFormatter(step.Format, formatters)
+	return ok
+}
+
+// validate - check format arguments
+func (step FormatStep) validate() error {
+	switch step.Format {
+	case FormatDate:
+		_, _, err := dateArgs(step.args)
+		return err
+	case FormatNumber, FormatCurrency, FormatPercent:
+		_, err := numberArgs(step.Format, step.Args)
+		return err
+	case FormatTruncate:
+		_, err := truncate("", step.Args)
+		return err
+	}
+	return nil
+}
+
+// String - format as written ":date(long)"
+func (step FormatStep) String() string {
+	if step.args == "" {
+		return step.Format
+	}
+	return step.Format + "(" + step.args + ")"
+}
+
+// truncate - :truncate(max chars, suffix) cuts text to max chars
+// and adds suffix (if any) when text is cut: :truncate(20, …)
+func truncate(text string, args []string) (string, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return text, errors.New("expected max chars and optional suffix, e.g. :truncate(20, …)")
+	}
+	max, err := strconv.Atoi(args[0])
+	if err != nil || max < 0 {
+		return text, fmt.Errorf("invalid max chars [%s]", args[0])
+	}
+
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text, nil
+	}
+	text = string(runes[:max])
+	if len(args) == 2 {
+		text += args[1]
+	}
+	return text, nil
+}
+
+// ApplyFormat - apply formatting to the given content based on the formatter.
+// Formatters registered by RegisterFormatter are applied without arguments,
+// content is left as is when they fail
 func (p *ParamFormatter) ApplyFormat(format string, content []byte) []byte {
+	if isFormatWord(strings.TrimPrefix(format, ":")) {
+		return applyFormat(format, content)
+	}
+	if fn, ok := customFormatter(format, nil); ok {
+		if s, err := fn(string(content), nil); err == nil {
+			return []byte(s)
+		}
+	}
+	return content
+}
+
+// applyFormat - ApplyFormat of text formats
+func applyFormat(format string, content []byte) []byte {
 	switch format {
 	case FormatLower:
 		return bytes.ToLower(content)
@@ -61,6 +205,8 @@ func (p *ParamFormatter) ApplyFormat(format string, content []byte) []byte {
 	case FormatTitle:
 		titleCaser := cases.Title(language.Und)
 		return []byte(titleCaser.String(string(content)))
+	case FormatTrim:
+		return bytes.TrimSpace(content)
 	case FormatCapitalize:
 		content = bytes.TrimSpace(content)
 		if len(content) > 0 {
@@ -80,6 +226,9 @@ func (p *ParamFormatter) String() string {
 	if p == nil {
 		return ""
 	}
-	s := p.Format
+	var s string
+	for _, step := range p.Steps {
+		s += step.String()
+	}
 	return s
 }
@@ -0,0 +1,156 @@
+package docxplate
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// currencyPlacement - where currency symbol goes around amount
+type currencyPlacement int8
+
+const (
+	currencyBefore      currencyPlacement = iota // $1,234.56 (en and not listed)
+	currencyBeforeSpace                          // € 1.234,56 (nl)
+	currencyAfter                                // 1 234,56 € (lv)
+)
+
+// currencyPlacements - placement by locale as in CLDR standard currency pattern.
+// Language with region wins over language: pt-PT "1 234,56 €", pt "R$ 1.234,56".
+// Space is no-break space
+var currencyPlacements = map[string]currencyPlacement{
+	"bg": currencyAfter, "cs": currencyAfter, "da": currencyAfter, "de": currencyAfter,
+	"el": currencyAfter, "es": currencyAfter, "et": currencyAfter, "fi": currencyAfter,
+	"fr": currencyAfter, "hr": currencyAfter, "hu": currencyAfter, "is": currencyAfter,
+	"it": currencyAfter, "lt": currencyAfter, "lv": currencyAfter, "nb": currencyAfter,
+	"nn": currencyAfter, "no": currencyAfter, "pl": currencyAfter, "pt-PT": currencyAfter,
+	"ro": currencyAfter, "ru": currencyAfter, "sk": currencyAfter, "sl": currencyAfter,
+	"sr": currencyAfter, "sv": currencyAfter, "uk": currencyAfter,
+
+	"nl": currencyBeforeSpace, "pt": currencyBeforeSpace, "de-AT": currencyBeforeSpace,
+	"de-CH": currencyBeforeSpace, "it-CH": currencyBeforeSpace,
+
+	"es-MX": currencyBefore, "es-US": currencyBefore, "es-419": currencyBefore,
+}
+
+// currencyPlacementOf - placement of language with region, then of language.
+// Region must be given, not guessed: pt is pt-BR, not pt-PT
+func currencyPlacementOf(locale language.Tag) currencyPlacement {
+	base, _ := locale.Base()
+	if region, conf := locale.Region(); conf == language.Exact {
+		if placement, ok := currencyPlacements[base.String()+"-"+region.String()]; ok {
+			return placement
+		}
+	}
+	return currencyPlacements[base.String()]
+}
+
+// formatNumber - :number(decimals, locale), :percent(decimals, locale)
+// and :currency(code, locale) of number value or number text.
+// Locale of arguments wins over locale of template. Empty value stays empty
+func formatNumber(format string, typed any, text string, args []string, locale language.Tag) (string, error) {
+	na, err := numberArgs(format, args)
+	if err != nil {
+		return text, err
+	}
+	if na.locale != language.Und {
+		locale = na.locale
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return "", nil
+	}
+	x, err := toFloat(typed, text)
+	if err != nil {
+		return text, err
+	}
+
+	printer := message.NewPrinter(locale)
+	switch format {
+	case FormatPercent:
+		return printer.Sprint(number.Percent(x, na.options()...)), nil
+	case FormatCurrency:
+		scale, _ := currency.Standard.Rounding(na.currency)
+		amount := printer.Sprint(number.Decimal(x, number.Scale(scale)))
+		symbol := printer.Sprint(currency.NarrowSymbol(na.currency))
+		switch currencyPlacementOf(locale) {
+		case currencyAfter:
+			return amount + "\u00a0" + symbol, nil
+		case currencyBeforeSpace:
+			return symbol + "\u00a0" + amount, nil
+		}
+		return symbol + amount, nil
+	default:
+		return printer.Sprint(number.Decimal(x, na.options()...)), nil
+	}
+}
+
+// toFloat - number of typed value or its text (ints, json.Number, named types..)
+func toFloat(typed any, text string) (float64, error) {
+	if x, ok := typed.(float64); ok {
+		return x, nil
+	}
+
+	x, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil {
+		return 0, fmt.Errorf("not a number [%s]", text)
+	}
+	return x, nil
+}
+
+// numberFormatArgs - parsed arguments of number formats
+type numberFormatArgs struct {
+	decimals int // -1 when not given
+	currency currency.Unit
+	locale   language.Tag
+}
+
+// numberArgs - arguments of number formats in any order:
+// (2), (2, lv), (lv), (EUR), (EUR, en-US)
+func numberArgs(format string, args []string) (numberFormatArgs, error) {
+	na := numberFormatArgs{decimals: -1}
+
+	for _, arg := range args {
+		if d, err := strconv.Atoi(arg); err == nil && format != FormatCurrency {
+			if d < 0 || d > 20 {
+				return na, fmt.Errorf("decimals out of range [%d]", d)
+			}
+			na.decimals = d
+			continue
+		}
+
+		if format == FormatCurrency && na.currency == (currency.Unit{}) {
+			unit, err := currency.ParseISO(arg)
+			if err != nil {
+				return na, fmt.Errorf("currency [%s]: %w", arg, err)
+			}
+			na.currency = unit
+			continue
+		}
+
+		tag, err := language.Parse(arg)
+		if err != nil {
+			return na, fmt.Errorf("locale [%s]: %w", arg, err)
+		}
+		na.locale = tag
+	}
+
+	if format == FormatCurrency && na.currency == (currency.Unit{}) {
+		return na, errors.New("currency code is required, e.g. :currency(EUR)")
+	}
+	return na, nil
+}
+
+// options - number options of arguments
+func (na numberFormatArgs) options() []number.Option {
+	if na.decimals < 0 {
+		return nil
+	}
+	return []number.Option{number.Scale(na.decimals)}
+}
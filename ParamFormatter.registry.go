@@ -0,0 +1,78 @@
+package docxplate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// FormatFunc - custom formatter. Value is typed value of param
+// when formatter is the first one of chain, otherwise text of previous formatter.
+// Args are formatter arguments: {{IBAN :iban(4, -)}} --> [4 -]
+type FormatFunc func(value any, args []string) (string, error)
+
+// formatters registered for all templates
+var (
+	customFormattersMu sync.RWMutex
+	customFormatters   = map[string]FormatFunc{}
+)
+
+// RegisterFormatter - add custom formatter for all templates:
+//
+//	docxplate.RegisterFormatter("iban", func(value any, args []string) (string, error) { .. })
+//	{{IBAN :iban}}
+//
+// Registering the same name again replaces formatter.
+// Panics when name is not a word, is taken by built-in formatter, trigger or vmerge mark,
+// or fn is nil
+func RegisterFormatter(name string, fn FormatFunc) {
+	name = formatterName(name, fn)
+
+	customFormattersMu.Lock()
+	defer customFormattersMu.Unlock()
+	customFormatters[name] = fn
+}
+
+// RegisterFormatter - add custom formatter for this template only,
+// it wins over formatter of the same name registered for all templates.
+// Panics the same way as package RegisterFormatter
+func (t *Template) RegisterFormatter(name string, fn FormatFunc) {
+	name = formatterName(name, fn)
+
+	if t.formatters == nil {
+		t.formatters = map[string]FormatFunc{}
+	}
+	t.formatters[name] = fn
+}
+
+// reFormatterName - name as it can be written in placeholder
+var reFormatterName = regexp.MustCompile(`^\w+$`)
+
+// formatterName - valid name of custom formatter "iban" (or ":iban") --> "iban"
+func formatterName(name string, fn FormatFunc) string {
+	name = strings.ToLower(strings.TrimPrefix(name, ":"))
+
+	switch {
+	case fn == nil:
+		panic(fmt.Sprintf("docxplate: formatter [%s] is nil", name))
+	case !reFormatterName.MatchString(name):
+		panic(fmt.Sprintf("docxplate: invalid formatter name [%s]", name))
+	case isFormatWord(name), isTriggerWord(name), isSliceWord(name), ":"+name == ParamVMerge, ":"+name == ParamDefault:
+		panic(fmt.Sprintf("docxplate: formatter name [%s] is reserved", name))
+	}
+	return name
+}
+
+// customFormatter - formatter of template (if any) or registered for all templates
+func customFormatter(name string, local map[string]FormatFunc) (FormatFunc, bool) {
+	name = strings.TrimPrefix(name, ":")
+	if fn, ok := local[name]; ok {
+		return fn, true
+	}
+
+	customFormattersMu.RLock()
+	defer customFormattersMu.RUnlock()
+	fn, ok := customFormatters[name]
+	return fn, ok
+}
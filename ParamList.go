@@ -1,9 +1,16 @@
 package docxplate
 
 import (
+	"bytes"
+	"encoding"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -30,19 +37,44 @@ func (params ParamList) Len() int {
 // 2) Now convert JSON to map[string]any
 // 3) Clear params from nil
 func AnyToParams(v any) ParamList {
+	params, err := anyToParams(v)
+	if err != nil {
+		log.Printf("AnyToParams: %s", err)
+	}
+	return params
+}
+
+// anyToParams - AnyToParams with error
+func anyToParams(v any) (ParamList, error) {
 	// to JSON output
-	buf, _ := json.MarshalIndent(v, "", "\t")
-	return JSONToParams(buf)
+	buf, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return nil, &ParamsError{Err: err}
+	}
+	return jsonToParams(buf)
 }
 
 // JSONToParams - load params from JSON
 func JSONToParams(buf []byte) ParamList {
+	params, err := jsonToParams(buf)
+	if err != nil {
+		log.Printf("JSONToParams: %s", err)
+	}
+	return params
+}
 
-	// to map
+// jsonToParams - JSONToParams with error
+func jsonToParams(buf []byte) (ParamList, error) {
+
+	// to map, numbers as they are written: no float64 rounding or 1e+06
 	m := map[string]any{}
-	if err := json.Unmarshal(buf, &m); err != nil {
-		log.Printf("JSONToParams: %s", err)
-		return nil
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return nil, &ParamsError{Err: err}
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, &ParamsError{Err: errors.New("invalid data after top-level JSON object")}
 	}
 
 	// to filtered/clean map
@@ -51,41 +83,17 @@ func JSONToParams(buf []byte) ParamList {
 		// use Walk func built-in logic to assign keys
 	})
 
-	return params
+	return params, nil
 }
 
-// walk map[string]any and collect valid params
+// walk map[string]any and collect valid params, nil values are null params
 func mapToParams(m map[string]any) ParamList {
 	var params ParamList
 	for mKey, mVal := range m {
 
 		p := NewParam(mKey)
+		anyToParam(p, mVal)
 
-		switch v := mVal.(type) {
-		case map[string]any:
-			p.Type = StructParam
-			p.Params = mapToParams(v)
-			p.SetValue(v)
-		case []any:
-			p.Type = SliceParam
-			p.Params = sliceToParams(v)
-			p.SetValue(v)
-		case *Image:
-			imgVal, err := processImage(v)
-			if err != nil {
-				log.Printf("ProcessImage: %s", err)
-				continue
-			}
-			p.Type = ImageParam
-			p.SetValue(imgVal)
-		default:
-			p.Type = StringParam
-			p.SetValue(mVal)
-		}
-
-		if mVal == nil && p.Params == nil {
-			continue
-		}
 		params = append(params, p)
 
 	}
@@ -101,15 +109,7 @@ func sliceToParams(arr []any) ParamList {
 		// Use index +1 because in template for user not useful see
 		// 0 as start number. Only programmers will understand
 		p := NewParam(i + 1)
-
-		switch v := val.(type) {
-		case map[string]any:
-			p.Type = StructParam
-			p.Params = mapToParams(v)
-		default:
-			p.Type = StringParam
-			p.SetValue(v)
-		}
+		anyToParam(p, val)
 
 		if val == nil && p.Params == nil {
 			continue
@@ -120,56 +120,123 @@ func sliceToParams(arr []any) ParamList {
 	return params
 }
 
-// StructToParams - walk struct and collect valid params
+// anyToParam - set param type and value of map or slice item.
+// Decoded JSON types are handled directly, anything else by reflection
+func anyToParam(p *Param, v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		p.Type = StructParam
+		p.Params = mapToParams(v)
+		p.SetValue(v)
+	case []any:
+		p.Type = SliceParam
+		p.Params = sliceToParams(v)
+		p.SetValue(v)
+	case *Image:
+		p.Type = ImageParam
+		p.image = v
+		p.TypedValue = v
+	case nil:
+		p.setNull()
+	case string, bool, float64, int, json.Number:
+		p.Type = StringParam
+		p.SetValue(v)
+	default:
+		reflectToParam(p, reflect.ValueOf(v))
+	}
+}
+
+// StructToParams - walk struct and collect valid params.
+// Params are named by `docx` field tag, or by field name if there is none:
+//
+//	Name    string  `docx:"name"`       // {{name}}
+//	Phone   string  `docx:",omitempty"` // no param when empty
+//	Address Address `docx:",inline"`    // {{Street}} instead of {{Address.Street}}
+//	Secret  string  `docx:"-"`          // never a param
+//
+// Embedded structs are flattened the same way as encoding/json does it.
+// Params of failed DocxValue are left out
 func StructToParams(paramStruct any) ParamList {
+	params := structToParams(paramStruct)
+	params.Walk(func(p *Param) {
+		// use Walk func built-in logic to assign keys
+	})
+
+	params, errs := params.dropErrors()
+	for _, err := range errs {
+		log.Printf("StructToParams: %s", err)
+	}
+
+	return params
+}
+
+// structToParams - StructToParams without keys assigned
+func structToParams(paramStruct any) ParamList {
 	var params ParamList
-	var keys reflect.Type
 	var vals reflect.Value
 	var ok bool
 
 	if vals, ok = paramStruct.(reflect.Value); !ok {
 		vals = reflect.ValueOf(paramStruct)
 	}
-	keys = vals.Type()
-
-	keynum := keys.NumField()
-	for i := 0; i < keynum; i++ {
-		key := keys.Field(i)
-		val := vals.Field(i)
-
-		if val.Kind() == reflect.Ptr {
-			val = val.Elem()
-		}
 
-		if !val.IsValid() {
+	for _, field := range structFields(vals.Type()) {
+		val, ok := fieldByIndex(vals, field.index)
+		if !ok {
 			continue
 		}
 
-		p := NewParam(key.Name)
-		switch val.Kind() {
-		case reflect.Struct:
-			reflectStructToParams(p, val)
-		case reflect.Slice:
-			reflectSliceToParams(p, val)
-		default:
-			p.Type = StringParam
-			p.SetValue(val)
-		}
-
-		if p == nil {
+		if field.omitEmpty && isEmptyValue(val) {
 			continue
 		}
 
+		p := NewParam(field.name)
+		reflectToParam(p, val)
+
 		params = append(params, p)
 	}
 
-	params.Walk(func(p *Param) {
-		// use Walk func built-in logic to assign keys
-	})
-
 	return params
 }
 
+// indirect - value behind pointers and interfaces, invalid if any of them is nil
+func indirect(val reflect.Value) reflect.Value {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		val = val.Elem()
+	}
+	return val
+}
+
+// reflectToParam - set param type and value by reflect kind
+func reflectToParam(p *Param, val reflect.Value) {
+	val = indirect(val)
+
+	if dv, ok := docxValuer(val); ok {
+		docxValueToParam(p, dv)
+		return
+	}
+
+	switch val.Kind() {
+	case reflect.Struct:
+		reflectStructToParams(p, val)
+	case reflect.Map:
+		reflectMapToParams(p, val)
+	case reflect.Slice, reflect.Array:
+		// []byte is text, not a list of numbers
+		if val.Type().Elem().Kind() == reflect.Uint8 {
+			p.Type = StringParam
+			p.SetValue(string(val.Bytes()))
+			return
+		}
+		reflectSliceToParams(p, val)
+	case reflect.Invalid:
+		p.setNull()
+	default:
+		p.Type = StringParam
+		p.SetValue(val)
+	}
+}
+
 // reflectStructToParams - map struct of reflect to params include special param type process
 func reflectStructToParams(p *Param, val reflect.Value) {
 	if !val.CanInterface() {
@@ -178,23 +245,45 @@ func reflectStructToParams(p *Param, val reflect.Value) {
 	}
 
 	if image, ok := val.Interface().(Image); ok {
-		imgVal, err := processImage(&image)
-		if err != nil {
-			log.Printf("ProcessImage: %s", err)
-			p = nil
-			return
-		}
 		p.Type = ImageParam
-		p.SetValue(imgVal)
+		p.image = &image
+		p.TypedValue = p.image
+		return
+	}
+
+	// time.Time and alike are single values, as in encoding/json
+	if tm, ok := val.Interface().(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		p.Type = StringParam
+		p.Value = string(text)
+		p.TypedValue = val.Interface()
+		p.err = err
 		return
 	}
 
 	p.Type = StructParam
-	p.Params = StructToParams(val)
+	p.Params = structToParams(val)
 
 }
 
-// reflectSliceToParams - map slice of reflect to params
+// reflectMapToParams - map of reflect to params, keys sorted
+// so params always come in the same order
+func reflectMapToParams(p *Param, val reflect.Value) {
+	p.Type = StructParam
+
+	keys := val.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+
+	for _, key := range keys {
+		itemParam := NewParam(fmt.Sprint(key))
+		reflectToParam(itemParam, val.MapIndex(key))
+		p.Params = append(p.Params, itemParam)
+	}
+}
+
+// reflectSliceToParams - map slice or array of reflect to params
 func reflectSliceToParams(p *Param, val reflect.Value) {
 	p.Type = SliceParam
 
@@ -202,36 +291,40 @@ func reflectSliceToParams(p *Param, val reflect.Value) {
 		// Use index +1 because in template for user not useful see
 		// 0 as start number. Only programmers will understand
 		itemParam := NewParam(i + 1)
-		itemVal := val.Index(i)
-
-		if itemVal.Kind() == reflect.Ptr {
-			itemVal = itemVal.Elem()
-		}
+		itemVal := indirect(val.Index(i))
 
 		if !itemVal.IsValid() {
 			continue
 		}
 
-		switch itemVal.Kind() {
-		case reflect.Struct:
-			reflectStructToParams(itemParam, itemVal)
-		default:
-			itemParam.Type = StringParam
-			itemParam.SetValue(itemVal)
-		}
+		reflectToParam(itemParam, itemVal)
+		p.Params = append(p.Params, itemParam)
+	}
+}
 
-		if itemParam == nil {
+// dropErrors - params without those which values failed, and their errors
+func (params ParamList) dropErrors() (ParamList, []error) {
+	var errs []error
+	var ret ParamList
+	for _, p := range params {
+		if p.err != nil {
+			errs = append(errs, &ValueError{Key: p.AbsoluteKey, Err: p.err})
 			continue
 		}
 
-		p.Params = append(p.Params, itemParam)
+		var nestedErrs []error
+		p.Params, nestedErrs = p.Params.dropErrors()
+		errs = append(errs, nestedErrs...)
+
+		ret = append(ret, p)
 	}
+	return ret, errs
 }
 
 // Parse row content to param list
-func rowParams(row []byte) ParamList {
+func rowParams(row []byte, d *delimiters) ParamList {
 	// extract from raw contents
-	matches := reParamExtract.FindAllSubmatch(row, -1)
+	matches := d.reParam.FindAllSubmatch(row, -1)
 
 	if matches == nil || matches[0] == nil {
 		return nil
@@ -240,11 +333,15 @@ func rowParams(row []byte) ParamList {
 	var params ParamList
 	for _, match := range matches {
 		p := NewParam(string(match[2]))
+		p.delims = d
 		p.RowPlaceholder = string(match[0])
 		p.Separator = string(match[3])
 		p.VMerge = isVMergeMark(match[4])
-		p.Trigger = NewParamTrigger(match[4])
+		p.Directive = string(match[1]) == DirectivePrefix
+		trs, _ := placeholderTriggers(p.Directive, match[4])
+		p.setTriggers(trs)
 		p.Formatter = NewFormatter(match[4])
+		p.modifiers = strings.TrimSpace(string(match[4]))
 		params = append(params, p)
 	}
 	return params
@@ -269,14 +366,69 @@ func (params ParamList) WalkWithEnd(fn func(*Param) bool) {
 	}
 }
 
-// FindAllByKey - returns all Params matching the given key
+// FindAllByKey - returns all Params matching the given key.
+// Key with slice indexes {{Contracts.2.Items.Name}} returns params of that item only
 func (params ParamList) FindAllByKey(key string) ParamList {
+	if compact, item, ok := params.indexedKey(key); ok {
+		return params.FindAllByKey(compact).within(item)
+	}
+
 	keySlice := strings.Split(key, ".")
 	var ret ParamList
 	params.findAllByKey(nil, nil, 0, 1, keySlice, &ret)
 	return ret
 }
 
+// indexedKey - key without slice indexes and key of its innermost slice item
+// Contracts.2.Items.Name --> Contracts.Items.Name, Contracts.2
+// Number is an index only of slice item, map keys stay: Years.2024.Items.Name
+func (params ParamList) indexedKey(key string) (compact, item string, ok bool) {
+	parts := strings.Split(key, ".")
+	var kept []string
+	for i, part := range parts {
+		if _, err := strconv.Atoi(part); err == nil && i > 0 {
+			if p := params.findByAbsoluteKey(strings.Join(parts[:i+1], ".")); p != nil && p.parent != nil && p.parent.Type == SliceParam {
+				item = p.AbsoluteKey
+				continue
+			}
+		}
+		kept = append(kept, part)
+	}
+	return strings.Join(kept, "."), item, item != ""
+}
+
+// findByAbsoluteKey - param of given absolute key, nil if none
+func (params ParamList) findByAbsoluteKey(key string) *Param {
+	var found *Param
+	params.WalkWithEnd(func(p *Param) bool {
+		if found == nil && p.AbsoluteKey == key {
+			found = p
+		}
+		return found != nil || !strings.HasPrefix(key, p.AbsoluteKey+".")
+	})
+	return found
+}
+
+// within - params of given slice item, indexed from 1 again.
+// Copies are indexed, params of list are shared with others
+func (params ParamList) within(item string) ParamList {
+	var ret ParamList
+	for _, p := range params {
+		if p.AbsoluteKey == item || strings.HasPrefix(p.AbsoluteKey, item+".") {
+			ret = append(ret, p)
+		}
+	}
+	if len(ret) > 0 {
+		base := ret[0].Index - 1
+		for i, p := range ret {
+			cp := *p
+			cp.Index -= base
+			ret[i] = &cp
+		}
+	}
+	return ret
+}
+
 func (params ParamList) findAllByKey(privParamList, paramList []int, offset, depth int, key []string, paramsIn *ParamList) ([]int, int) {
 	if depth > len(key) {
 		return nil, 0
@@ -0,0 +1,162 @@
+package docxplate
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// structField - struct field to become a param
+type structField struct {
+	name      string
+	index     []int // reflect field index, through flattened structs too
+	tagged    bool  // name given by tag
+	omitEmpty bool
+}
+
+// structFields - fields of struct type to become params, in order of declaration.
+// Embedded and `inline` structs are flattened, name conflicts are resolved
+// like in encoding/json: less nested field wins, then the tagged one,
+// otherwise all conflicting fields are left out
+func structFields(typ reflect.Type) []structField {
+	var fields []structField
+
+	var walk func(typ reflect.Type, index []int, visited []reflect.Type)
+	walk = func(typ reflect.Type, index []int, visited []reflect.Type) {
+		for i := 0; i < typ.NumField(); i++ {
+			sf := typ.Field(i)
+
+			ftyp := sf.Type
+			if ftyp.Kind() == reflect.Ptr {
+				ftyp = ftyp.Elem()
+			}
+			if !sf.IsExported() && !(sf.Anonymous && ftyp.Kind() == reflect.Struct) {
+				continue
+			}
+
+			name, opts, skip := fieldTag(sf)
+			if skip {
+				continue
+			}
+
+			fieldIndex := append(slices.Clone(index), i)
+
+			// flatten embedded or inline structs, but only once per path
+			// so recursive types can't loop forever
+			inline := (sf.Anonymous && name == "") || slices.Contains(opts, "inline")
+			if inline && ftyp.Kind() == reflect.Struct && !slices.Contains(visited, ftyp) {
+				walk(ftyp, fieldIndex, append(slices.Clone(visited), ftyp))
+				continue
+			}
+
+			if !sf.IsExported() {
+				continue
+			}
+
+			field := structField{
+				name:      name,
+				index:     fieldIndex,
+				tagged:    name != "",
+				omitEmpty: slices.Contains(opts, "omitempty"),
+			}
+			if !field.tagged {
+				field.name = sf.Name
+			}
+			fields = append(fields, field)
+		}
+	}
+	walk(typ, nil, []reflect.Type{typ})
+
+	return dominantFields(fields)
+}
+
+// dominantFields - leave one field for every name, or none on conflict
+func dominantFields(fields []structField) []structField {
+	byName := map[string][]structField{}
+	for _, field := range fields {
+		byName[field.name] = append(byName[field.name], field)
+	}
+
+	var ret []structField
+	for _, field := range fields {
+		dominant, ok := dominantField(byName[field.name])
+		if ok && slices.Equal(dominant.index, field.index) {
+			ret = append(ret, field)
+		}
+	}
+	return ret
+}
+
+// dominantField - less nested field of the same name, tagged one if nested equally
+func dominantField(fields []structField) (structField, bool) {
+	depth := len(fields[0].index)
+	for _, field := range fields {
+		depth = min(depth, len(field.index))
+	}
+
+	var candidates []structField
+	var tagged []structField
+	for _, field := range fields {
+		if len(field.index) != depth {
+			continue
+		}
+		candidates = append(candidates, field)
+		if field.tagged {
+			tagged = append(tagged, field)
+		}
+	}
+
+	switch {
+	case len(candidates) == 1:
+		return candidates[0], true
+	case len(tagged) == 1:
+		return tagged[0], true
+	}
+	return structField{}, false
+}
+
+// fieldTag - name and options from `docx` tag, other tags (`json`) are not used.
+// `docx:"-"` skips field, `docx:"-,"` names it "-"
+func fieldTag(sf reflect.StructField) (name string, opts []string, skip bool) {
+	tag := sf.Tag.Get("docx")
+	if tag == "-" {
+		return "", nil, true
+	}
+
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:], false
+}
+
+// fieldByIndex - field value of flattened struct,
+// not ok if any embedded struct pointer on the way is nil
+func fieldByIndex(val reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				return reflect.Value{}, false
+			}
+			val = val.Elem()
+		}
+		val = val.Field(x)
+	}
+	return val, true
+}
+
+// isEmptyValue - value left out with `omitempty`, same as in encoding/json
+func isEmptyValue(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return val.Len() == 0
+	case reflect.Bool:
+		return !val.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return val.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return val.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return val.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package docxplate
+
+// Loop keys of every slice item: {{Friends.#number}}. {{Friends.Name}}
+const (
+	LoopIndex  = "#index"  // 0, 1, 2..
+	LoopNumber = "#number" // 1, 2, 3..
+	LoopCount  = "#count"  // items in slice
+	LoopFirst  = "#first"  // true for the first item
+	LoopLast   = "#last"   // true for the last item
+)
+
+var loopKeys = []string{LoopIndex, LoopNumber, LoopCount, LoopFirst, LoopLast}
+
+// setLoopMeta - loop keys of items of all slices
+func (params ParamList) setLoopMeta() {
+	for _, p := range params {
+		if p.Type == SliceParam {
+			p.setLoopMeta()
+		}
+		p.Params.setLoopMeta()
+	}
+}
+
+// setLoopMeta - loop keys of items of slice param, replaces old ones.
+// Items being slices themselves get none, their params are items
+func (p *Param) setLoopMeta() {
+	count := len(p.Params)
+	for i, item := range p.Params {
+		if item.Type == SliceParam {
+			continue
+		}
+
+		var params ParamList
+		for _, p2 := range item.Params {
+			if !inSlice(p2.Key, loopKeys) {
+				params = append(params, p2)
+			}
+		}
+		item.Params = append(params, loopMeta(i, count)...)
+	}
+}
+
+// loopMeta - loop key params of item at index i of count items
+func loopMeta(i, count int) ParamList {
+	meta := map[string]any{
+		LoopIndex:  i,
+		LoopNumber: i + 1,
+		LoopCount:  count,
+		LoopFirst:  i == 0,
+		LoopLast:   i == count-1,
+	}
+
+	var params ParamList
+	for _, key := range loopKeys {
+		mp := NewParam(key)
+		mp.Type = StringParam
+		mp.SetValue(meta[key])
+		params = append(params, mp)
+	}
+	return params
+}
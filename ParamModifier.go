@@ -0,0 +1,124 @@
+package docxplate
+
+import "strings"
+
+// modifier - single ":name(args)" part of placeholder params
+// {{SignedAt :date(long, Europe/Riga):upper}} --> date(long, Europe/Riga), upper
+type modifier struct {
+	name    string // lower case, without ":"
+	args    string // as written, without parentheses
+	hasArgs bool   // parentheses written, args can be empty :=()
+}
+
+// parseModifiers - ":date(long):empty:remove:row" to its modifiers.
+// Quoted default |"n/a" (only first) is :default(n/a) modifier.
+// Text not starting with ":" or "|" has no modifiers
+func parseModifiers(raw string) []modifier {
+	raw = strings.TrimSpace(raw)
+
+	var mods []modifier
+	if text, rest, ok := quotedDefault(raw); ok {
+		mods = append(mods, modifier{name: ParamDefault[1:], args: text, hasArgs: true})
+		raw = rest
+	}
+
+	for strings.HasPrefix(raw, ":") {
+		raw = raw[1:]
+
+		end := strings.IndexAny(raw, ":(")
+		if end < 0 {
+			end = len(raw)
+		}
+		m := modifier{name: strings.ToLower(strings.TrimSpace(raw[:end]))}
+		raw = raw[end:]
+
+		if strings.HasPrefix(raw, "(") {
+			end = closingParen(raw)
+			if end < 0 {
+				end = len(raw)
+				raw += ")"
+			}
+			m.args = raw[1:end]
+			m.hasArgs = true
+			raw = raw[end+1:]
+		}
+
+		mods = append(mods, m)
+	}
+
+	return mods
+}
+
+// closingParen - index of ")" closing "(" s starts with, -1 if none.
+// Args can hold parentheses themselves: :match(^(paid|sent)$)
+func closingParen(s string) int {
+	var depth int
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// placeholderModifiers - modifiers of placeholder by kind.
+// Every modifier belongs to one kind only, so formats, trigger,
+// slice modifiers and vmerge mark never take each other's words
+type placeholderModifiers struct {
+	formats []modifier // in order as written, applied left to right. Custom and unknown too
+	trigger []modifier // :empty:remove:row
+	slice   []modifier // :sort(Amount,desc):limit(5), in order as written
+	vmerge  bool       // :vmerge
+	def     *modifier  // :default(n/a) or |"n/a", last one wins
+}
+
+// splitModifiers - ":trim:upper:empty:remove:row:vmerge" to its modifiers by kind
+func splitModifiers(raw string) placeholderModifiers {
+	var mods placeholderModifiers
+	for _, m := range parseModifiers(raw) {
+		switch {
+		case ":"+m.name == ParamVMerge:
+			mods.vmerge = true
+		case ":"+m.name == ParamDefault:
+			mods.def = &m
+		case isTriggerWord(m.name):
+			mods.trigger = append(mods.trigger, m)
+		case isSliceWord(m.name):
+			mods.slice = append(mods.slice, m)
+		default:
+			mods.formats = append(mods.formats, m)
+		}
+	}
+	return mods
+}
+
+// hasModifiers - does raw params part start with modifiers ":upper" or default |"n/a"
+func hasModifiers(raw string) bool {
+	return strings.HasPrefix(raw, ":") || strings.HasPrefix(raw, defaultMark)
+}
+
+// argList - comma separated args: :truncate(20, …) --> [20 …]
+func (m modifier) argList() []string {
+	if m.args == "" {
+		return nil
+	}
+	args := strings.Split(m.args, ",")
+	for i := range args {
+		args[i] = strings.TrimSpace(args[i])
+	}
+	return args
+}
+
+// String - modifier as written in placeholder ":date(long)"
+func (m modifier) String() string {
+	if m.args == "" {
+		return ":" + m.name
+	}
+	return ":" + m.name + "(" + m.args + ")"
+}
@@ -0,0 +1,259 @@
+package docxplate
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Slice modifiers - choose and order slice items a row is expanded to:
+// {{Items.Name :where(Active=true):sort(Amount,desc):limit(5)}}
+const (
+	SliceSort  = ":sort"  // :sort(Key) or :sort(Key,desc), numbers compared as numbers
+	SliceLimit = ":limit" // :limit(5) - first items only
+	SliceWhere = ":where" // :where(Key=value) or :where(Key!=value)
+)
+
+var sliceModifiers = []string{SliceSort, SliceLimit, SliceWhere}
+
+// isSliceWord - is "sort", "limit".. a slice modifier
+func isSliceWord(word string) bool {
+	return inSlice(":"+word, sliceModifiers)
+}
+
+// validateSlice - are args of slice modifier valid
+func (m modifier) validateSlice() error {
+	args := m.argList()
+	switch ":" + m.name {
+	case SliceSort:
+		if len(args) > 2 {
+			return fmt.Errorf("too many args [%s]", m.args)
+		}
+		if len(args) == 2 && !inSlice(strings.ToLower(args[1]), []string{"asc", "desc"}) {
+			return fmt.Errorf("order must be asc or desc, got [%s]", args[1])
+		}
+	case SliceLimit:
+		if n, err := strconv.Atoi(m.args); err != nil || n < 0 {
+			return fmt.Errorf("limit must be a number not below 0, got [%s]", m.args)
+		}
+	case SliceWhere:
+		if field, _, _ := whereArgs(m.args); field == "" {
+			return fmt.Errorf("condition must be Key=value or Key!=value, got [%s]", m.args)
+		}
+	}
+	return nil
+}
+
+// whereArgs - "Status != sent" --> Status, sent, true.
+// Empty field when there is no condition
+func whereArgs(args string) (field, value string, negate bool) {
+	sep := "="
+	if strings.Contains(args, "!=") {
+		sep, negate = "!=", true
+	}
+	field, value, ok := strings.Cut(args, sep)
+	if !ok {
+		return "", "", false
+	}
+	return strings.TrimSpace(field), strings.TrimSpace(value), negate
+}
+
+// applySlice - items chosen and ordered by slice modifiers left to right,
+// invalid modifiers are skipped (see Lint). Items itself are not changed
+func applySlice(items ParamList, mods []modifier) ParamList {
+	items = append(ParamList(nil), items...)
+	for _, m := range mods {
+		if m.validateSlice() != nil {
+			continue
+		}
+
+		args := m.argList()
+		switch ":" + m.name {
+		case SliceSort:
+			var field string
+			if len(args) > 0 {
+				field = args[0]
+			}
+			desc := len(args) == 2 && strings.EqualFold(args[1], "desc")
+			sort.SliceStable(items, func(i, j int) bool {
+				a, b := itemField(items[i], field), itemField(items[j], field)
+				switch {
+				case a == nil || b == nil:
+					// items without the key go last
+					return a != nil
+				case desc:
+					return compareValues(a, b.Value) > 0
+				}
+				return compareValues(a, b.Value) < 0
+			})
+		case SliceLimit:
+			if n, _ := strconv.Atoi(m.args); n < len(items) {
+				items = items[:n]
+			}
+		case SliceWhere:
+			field, value, negate := whereArgs(m.args)
+			var kept ParamList
+			for _, item := range items {
+				if p := itemField(item, field); p != nil && (compareValues(p, value) == 0) != negate {
+					kept = append(kept, item)
+				}
+			}
+			items = kept
+		}
+	}
+	return items
+}
+
+// itemField - param of slice item by key relative to item,
+// item itself for "." or empty key
+func itemField(item *Param, field string) *Param {
+	if field == "" || field == "." {
+		return item
+	}
+	return item.Params.findByKey(item.AbsoluteKey + "." + field)
+}
+
+// sliceView - params of row with slice items chosen and ordered by slice
+// modifiers of its placeholders, so all placeholders of the row are expanded
+// to the same items. Params of template are not changed
+type sliceView struct {
+	params ParamList      // params with items of view, row is rendered with them
+	order  map[string]int // position of view items by absolute key
+}
+
+// sliceView - view of slices of row placeholders with slice modifiers, nil if
+// there are none. Items of view have loop keys counting items as shown:
+// {{Users.#number}} of the first item shown is 1, whatever its index in data
+func (t *Template) sliceView(rowParams ParamList) *sliceView {
+	items := make(map[*Param]ParamList)
+	for _, rowParam := range rowParams {
+		mods := splitModifiers(rowParam.modifiers).slice
+		if len(mods) == 0 {
+			continue
+		}
+		for _, p := range t.params.slicesOf(rowParam.AbsoluteKey) {
+			if _, ok := items[p]; ok {
+				continue
+			}
+			items[p] = applySlice(p.Params, mods)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	view := &sliceView{order: make(map[string]int)}
+	for _, chosen := range items {
+		for i, item := range chosen {
+			view.order[item.AbsoluteKey] = i
+		}
+	}
+	view.params, _ = t.params.viewOf(items)
+	return view
+}
+
+// itemIndex - position of slice item in its slice as shown in view.
+// Nil view is no view: position in data
+func (view *sliceView) itemIndex(item *Param) int {
+	if view != nil {
+		if i, ok := view.order[item.AbsoluteKey]; ok {
+			return i
+		}
+	}
+	return indexInParent(item)
+}
+
+// indexInParent - position of slice item in its slice in data,
+// found by key as item may be a copy (FindAllByKey of indexed key)
+func indexInParent(item *Param) int {
+	for i, p := range item.parent.Params {
+		if p.AbsoluteKey == item.AbsoluteKey {
+			return i
+		}
+	}
+	return -1
+}
+
+// viewOf - params with items of slices replaced by chosen ones.
+// Slices and params holding them are copied, the rest are shared
+func (params ParamList) viewOf(items map[*Param]ParamList) (ParamList, bool) {
+	var ret ParamList
+	for i, p := range params {
+		vp := p.viewOf(items)
+		if vp == p {
+			continue
+		}
+		if ret == nil {
+			ret = slices.Clone(params)
+		}
+		ret[i] = vp
+	}
+	if ret == nil {
+		return params, false
+	}
+	return ret, true
+}
+
+// viewOf - param itself, or its copy if it's a slice of view or holds one.
+// Chosen items are copied to get loop keys of their own, which
+// keep keys and parent of item in data: Users.3.#number
+func (p *Param) viewOf(items map[*Param]ParamList) *Param {
+	chosen, isView := items[p]
+	if !isView {
+		chosen = p.Params
+	}
+	params, changed := chosen.viewOf(items)
+	if !isView && !changed {
+		return p
+	}
+
+	vp := *p
+	vp.Params = params
+	if !isView {
+		return &vp
+	}
+
+	vp.Params = make(ParamList, len(params))
+	for i, item := range params {
+		vitem := *item
+		vp.Params[i] = &vitem
+	}
+	vp.setLoopMeta()
+	for i, vitem := range vp.Params {
+		for _, lp := range vitem.Params {
+			if !inSlice(lp.Key, loopKeys) {
+				continue
+			}
+			lp.parent = chosen[i]
+			lp.Level = vitem.Level + 1
+			lp.AbsoluteKey = vitem.AbsoluteKey + "." + lp.Key
+			lp.CompactKey = vitem.CompactKey + "." + lp.Key
+			lp.delims = vitem.delims
+		}
+	}
+	return &vp
+}
+
+// slicesOf - all slice params of the deepest slice key is in:
+// Contracts.Items.Name --> every Items slice of every contract
+func (params ParamList) slicesOf(key string) ParamList {
+	key, _, _ = params.indexedKey(key)
+
+	var deepest string
+	var ret ParamList
+	params.WalkWithEnd(func(p *Param) bool {
+		if p.Type != SliceParam || (key != p.CompactKey && !strings.HasPrefix(key, p.CompactKey+".")) {
+			return false
+		}
+		switch {
+		case len(p.CompactKey) > len(deepest):
+			deepest, ret = p.CompactKey, ParamList{p}
+		case p.CompactKey == deepest:
+			ret = append(ret, p)
+		}
+		return false
+	})
+	return ret
+}
@@ -0,0 +1,89 @@
+package docxplate
+
+import "strings"
+
+// ParamState - what is known about value of param key
+type ParamState int8
+
+// Param states, each has its trigger: {{Phone :null:remove:row}}
+const (
+	ParamStateValue   ParamState = iota // key with value
+	ParamStateEmpty                     // key with empty value: "", empty slice or map
+	ParamStateNull                      // key with null value: JSON null, nil pointer, map or interface
+	ParamStateUnknown                   // no such key
+)
+
+// String - state name as its trigger word without ":"
+func (s ParamState) String() string {
+	switch s {
+	case ParamStateEmpty:
+		return "empty"
+	case ParamStateNull:
+		return "null"
+	case ParamStateUnknown:
+		return "unknown"
+	}
+	return "value"
+}
+
+// State - is param value known, null, empty or set
+func (p *Param) State() ParamState {
+	switch {
+	case p.unknown:
+		return ParamStateUnknown
+	case p.null:
+		return ParamStateNull
+	}
+
+	switch p.Type {
+	case StructParam, SliceParam:
+		if len(p.Params) == 0 {
+			return ParamStateEmpty
+		}
+	case StringParam:
+		if p.Value == "" {
+			return ParamStateEmpty
+		}
+	}
+	return ParamStateValue
+}
+
+// setNull - param of nil value
+func (p *Param) setNull() {
+	p.Type = StringParam
+	p.Value = ""
+	p.TypedValue = nil
+	p.null = true
+}
+
+// keyState - state of key placeholder left without value for.
+// Key inside of empty or null slice/map/struct is empty or null too:
+// {{Friends.Name}} of no friends is empty, not unknown
+func (params ParamList) keyState(key string) ParamState {
+	parts := strings.Split(key, ".")
+	for i := len(parts); i > 0; i-- {
+		p := params.findByKey(strings.Join(parts[:i], "."))
+		if p == nil {
+			continue
+		}
+
+		state := p.State()
+		if i == len(parts) || state == ParamStateEmpty || state == ParamStateNull {
+			return state
+		}
+		return ParamStateUnknown
+	}
+	return ParamStateUnknown
+}
+
+// findByKey - first param of given absolute or compact key at any depth
+func (params ParamList) findByKey(key string) *Param {
+	var found *Param
+	params.WalkWithEnd(func(p *Param) bool {
+		if found == nil && (p.AbsoluteKey == key || p.CompactKey == key) {
+			found = p
+		}
+		return found != nil
+	})
+	return found
+}
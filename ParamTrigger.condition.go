@@ -0,0 +1,166 @@
+package docxplate
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// triggers comparing value with the one given in trigger: :=(paid)
+var triggerComparisons = []string{
+	TriggerOnValue,
+	TriggerOnNotValue,
+	TriggerOnGreater,
+	TriggerOnLess,
+	TriggerOnContains,
+	TriggerOnMatch,
+}
+
+// validateValue - compared value of trigger, :=() and :!=() compare with empty value.
+// Bare := takes no value, it fires when key is set
+func (tr *ParamTrigger) validateValue() error {
+	if tr.On == TriggerOnIf {
+		return nil // key is optional, own value when not given
+	}
+
+	if !inSlice(tr.On, triggerComparisons) {
+		if tr.Value != "" {
+			return fmt.Errorf("trigger on [%s] takes no value", tr.On)
+		}
+		return nil
+	}
+
+	switch tr.On {
+	case TriggerOnGreater, TriggerOnLess, TriggerOnContains, TriggerOnMatch:
+		if tr.Value == "" {
+			return fmt.Errorf("trigger on [%s] needs value, e.g. %s(100)", tr.On, tr.On)
+		}
+	}
+
+	if tr.On == TriggerOnMatch {
+		re, err := regexp.Compile(tr.Value)
+		if err != nil {
+			return fmt.Errorf("trigger on [%s]: %w", tr.On, err)
+		}
+		tr.re = re
+	}
+	return nil
+}
+
+// triggerEnv - template settings and params triggers depend on
+type triggerEnv struct {
+	params      ParamList // to look up keys of :if(Key) in
+	strictEmpty bool      // :empty fires only on empty value, see WithStrictEmpty
+}
+
+// fires - does param value meet trigger condition.
+// State triggers fire only on their own state (:empty on unknown and null too
+// unless strict), comparisons only when there is a value (empty too).
+// Key of :if(Key) is looked up in params of env
+func (tr *ParamTrigger) fires(p *Param, env triggerEnv) bool {
+	if tr.On == TriggerOnIf {
+		if tr.Value == "" {
+			return !truthy(p)
+		}
+		return !truthy(env.params.lookupKey(p.AbsoluteKey, tr.Value))
+	}
+
+	state := p.State()
+	switch tr.On {
+	case TriggerOnUnknown:
+		return state == ParamStateUnknown
+	case TriggerOnNull:
+		return state == ParamStateNull
+	case TriggerOnEmpty:
+		return state == ParamStateEmpty || !env.strictEmpty && state != ParamStateValue
+	}
+
+	if state != ParamStateValue && state != ParamStateEmpty {
+		return false
+	}
+
+	switch tr.On {
+	case TriggerOnValue:
+		return !tr.hasValue || compareValues(p, tr.Value) == 0
+	case TriggerOnNotValue:
+		return compareValues(p, tr.Value) != 0
+	case TriggerOnGreater:
+		return compareValues(p, tr.Value) > 0
+	case TriggerOnLess:
+		return compareValues(p, tr.Value) < 0
+	case TriggerOnContains:
+		return strings.Contains(p.Value, tr.Value)
+	case TriggerOnMatch:
+		return tr.re != nil && tr.re.MatchString(p.Value)
+	}
+	return false
+}
+
+// compareValues - compare param value with given one: numbers as numbers
+// (0 == 0.00, 9 < 10), anything else as text
+func compareValues(p *Param, value string) int {
+	x, err := toFloat(p.TypedValue, p.Value)
+	if err == nil {
+		if y, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(p.Value, value)
+}
+
+// truthy - is param set and not false: true, non zero number, text, not empty slice..
+func truthy(p *Param) bool {
+	if p == nil || p.State() != ParamStateValue {
+		return false
+	}
+	if b, ok := p.TypedValue.(bool); ok {
+		return b
+	}
+	if p.Type != StringParam {
+		return true
+	}
+	if x, err := toFloat(p.TypedValue, p.Value); err == nil {
+		return x != 0
+	}
+	return !strings.EqualFold(p.Value, "false")
+}
+
+// lookupKey - param of key as seen from param of absolute key `from`:
+// in its slice item first, from the innermost one, then from the top.
+// Key itself is found first, so slice is a slice, not its first item.
+// {{Friends.2.Discount :if(IsVIP)}} --> Friends.2.IsVIP or IsVIP
+func (params ParamList) lookupKey(from, key string) *Param {
+	parts := strings.Split(from, ".")
+	for i := len(parts) - 1; i > 0; i-- {
+		if _, err := strconv.Atoi(parts[i-1]); err != nil {
+			continue // not a slice item
+		}
+		item := params.findByKey(strings.Join(parts[:i], "."))
+		if item == nil {
+			continue
+		}
+		if found := params.findByKey(item.AbsoluteKey + "." + key); found != nil {
+			return found
+		}
+		for _, found := range params.FindAllByKey(item.CompactKey + "." + key) {
+			if strings.HasPrefix(found.AbsoluteKey, item.AbsoluteKey+".") {
+				return found
+			}
+		}
+	}
+
+	if found := params.findByKey(key); found != nil {
+		return found
+	}
+	if found := params.FindAllByKey(key); len(found) > 0 {
+		return found[0]
+	}
+	return nil
+}
@@ -3,15 +3,21 @@ package docxplate
 import (
 	"bytes"
 	"fmt"
-	"log"
-	"strings"
+	"regexp"
 )
 
 // On - trigger events when command to aply
 const (
-	TriggerOnUnknown string = ":unknown"
-	TriggerOnEmpty   string = ":empty"
-	TriggerOnValue   string = ":="
+	TriggerOnUnknown  string = ":unknown"  // no such key
+	TriggerOnNull     string = ":null"     // key with null value
+	TriggerOnEmpty    string = ":empty"    // key with empty value
+	TriggerOnValue    string = ":="        // :=(paid) - value equals, bare := - key is set
+	TriggerOnNotValue string = ":!="       // :!=(0) - value does not equal
+	TriggerOnGreater  string = ":gt"       // :gt(100) - value greater than
+	TriggerOnLess     string = ":lt"       // :lt(100) - value less than
+	TriggerOnContains string = ":contains" // :contains(x) - value contains text
+	TriggerOnMatch    string = ":match"    // :match(^LV\d+$) - value matches regex
+	TriggerOnIf       string = ":if"       // :if(IsVIP) - other key is false, empty or unknown
 )
 
 // Command - what to do when triggered
@@ -28,103 +34,157 @@ const (
 	TriggerScopeList        = ":list"
 	TriggerScopeTable       = ":table"
 	TriggerScopeSection     = ":section" // table, list..
+	TriggerScopeParagraph   = ":paragraph"
 )
 
+// All trigger parts by kind
+var (
+	triggerOns = []string{
+		TriggerOnUnknown,
+		TriggerOnNull,
+		TriggerOnEmpty,
+		TriggerOnValue,
+		TriggerOnNotValue,
+		TriggerOnGreater,
+		TriggerOnLess,
+		TriggerOnContains,
+		TriggerOnMatch,
+		TriggerOnIf,
+	}
+	triggerCommands = []string{TriggerCommandRemove, TriggerCommandClear}
+	triggerScopes   = []string{
+		TriggerScopePlaceholder,
+		TriggerScopeCell,
+		TriggerScopeRow,
+		TriggerScopeList,
+		TriggerScopeTable,
+		TriggerScopeSection,
+		TriggerScopeParagraph,
+	}
+)
+
+// isTriggerWord - is "empty", "remove", "row".. part of trigger
+func isTriggerWord(word string) bool {
+	word = ":" + word
+	return inSlice(word, triggerOns) || inSlice(word, triggerCommands) || inSlice(word, triggerScopes)
+}
+
 // ParamTrigger - param trigger command
 // {{Key :On:Command:Scope}}
 // {{MyParam :empty:remove:list}} -- Read as: "`remove` `list` on `empty` value"
+// {{Status :=(cancelled):remove:row}} -- Read as: "`remove` `row` on value `cancelled`"
+// {{Discount :if(IsVIP):remove:row}} -- Read as: "keep `row` only if `IsVIP`, otherwise `remove` it"
 type ParamTrigger struct {
 	raw string
 
-	On      string
-	Command string
-	Scope   string
+	On       string
+	Value    string // compared value of On: :gt(100) --> 100, key of :if(IsVIP) --> IsVIP
+	hasValue bool   // value written, even empty one :=()
+	Command  string
+	Scope    string
+
+	re *regexp.Regexp // of :match
 }
 
-// NewParamTrigger - take raw ":empty:remove:list" and make trigger and its fields from it
+// NewParamTrigger - take raw ":empty:remove:list" and make trigger and its fields from it,
+// the first one when raw holds more triggers, see NewParamTriggers.
+// Invalid trigger is nil (reported as *TriggerError by Template.Render)
 func NewParamTrigger(raw []byte) *ParamTrigger {
-	raw = bytes.TrimSpace(raw)
-	raw = bytes.ToLower(raw)
-
-	// init with defaults
-	tr := &ParamTrigger{
-		raw:     string(raw),
-		On:      TriggerOnUnknown,
-		Command: TriggerCommandRemove,
-		Scope:   TriggerScopeRow,
+	trs := NewParamTriggers(raw)
+	if len(trs) == 0 {
+		return nil
 	}
+	return trs[0]
+}
+
+// NewParamTriggers - take raw ":empty:clear:cell:unknown:remove:row"
+// and make all triggers of it in written order.
+// Invalid triggers are nil (reported as *TriggerError by Template.Render)
+func NewParamTriggers(raw []byte) []*ParamTrigger {
+	trs, _ := parseParamTriggers(raw)
+	return trs
+}
+
+// parseParamTriggers - NewParamTriggers with error.
+// Every 3 trigger words in a row make one trigger.
+// No triggers and no error when raw holds no trigger words at all
+// (formatter or vmerge mark only)
+func parseParamTriggers(raw []byte) ([]*ParamTrigger, error) {
+	raw = bytes.TrimSpace(raw)
 
 	// Always must start with ":"
-	if !strings.HasPrefix(tr.raw, ":") {
-		return nil
+	if !hasModifiers(string(raw)) {
+		return nil, nil
 	}
 
-	// Remove the first ":" so split parts counting is more readable
-	// Split into parts
-	parts := strings.Split(tr.raw[1:], ":")
-
-	var countCommandParts = 0
-	for _, part := range parts {
-		switch part {
-		case "unknown", "empty", "=":
-			countCommandParts++
-			tr.On = ":" + part
-		case "remove", "clear":
-			countCommandParts++
-			tr.Command = ":" + part
-		case "placeholder", "cell", "row", "list", "table", "section":
-			countCommandParts++
-			tr.Scope = ":" + part
-		}
+	words := splitModifiers(string(raw)).trigger
+	if len(words)%3 != 0 {
+		return nil, ErrTriggerParts
 	}
 
-	if countCommandParts != 3 {
-		return nil
+	var trs []*ParamTrigger
+	for i := 0; i < len(words); i += 3 {
+		tr, err := newParamTriggerOf(words[i : i+3])
+		if err != nil {
+			return nil, err
+		}
+		trs = append(trs, tr)
 	}
+	return trs, nil
+}
 
-	if !tr.isValid() {
-		return nil
+// newParamTriggerOf - trigger of its on, command and scope words (in any order)
+func newParamTriggerOf(words []modifier) (*ParamTrigger, error) {
+	tr := &ParamTrigger{}
+
+	var raw string
+	for _, m := range words {
+		word := ":" + m.name
+		raw += word
+		if m.hasArgs {
+			raw += "(" + m.args + ")"
+		}
+
+		switch {
+		case inSlice(word, triggerOns) && tr.On == "":
+			tr.On = word
+			tr.Value = m.args
+			tr.hasValue = m.hasArgs
+		case inSlice(word, triggerCommands) && tr.Command == "":
+			tr.Command = word
+		case inSlice(word, triggerScopes) && tr.Scope == "":
+			tr.Scope = word
+		default:
+			return nil, ErrTriggerParts
+		}
 	}
+	tr.raw = raw
 
-	return tr
+	if err := tr.validate(); err != nil {
+		return nil, err
+	}
+	return tr, nil
 }
 
 // Validate trigger
-func (tr *ParamTrigger) isValid() bool {
+func (tr *ParamTrigger) validate() error {
 
 	// On
-	if !inSlice(tr.On, []string{
-		TriggerOnUnknown,
-		TriggerOnEmpty,
-		TriggerOnValue,
-	}) {
-		log.Printf("ERROR: No such trigger on [%s]", tr.On)
-		return false
+	if !inSlice(tr.On, triggerOns) {
+		return fmt.Errorf("no such trigger on [%s]", tr.On)
 	}
 
 	// Command
-	if !inSlice(tr.Command, []string{
-		TriggerCommandClear,
-		TriggerCommandRemove,
-	}) {
-		log.Printf("ERROR: No such trigger command [%s]", tr.Command)
-		return false
+	if !inSlice(tr.Command, triggerCommands) {
+		return fmt.Errorf("no such trigger command [%s]", tr.Command)
 	}
 
 	// Scope
-	if !inSlice(tr.Scope, []string{
-		TriggerScopePlaceholder,
-		TriggerScopeCell,
-		TriggerScopeRow,
-		TriggerScopeList,
-		TriggerScopeTable,
-		TriggerScopeSection,
-	}) {
-		log.Printf("ERROR: No such trigger scope [%s]", tr.Scope)
-		return false
+	if !inSlice(tr.Scope, triggerScopes) {
+		return fmt.Errorf("no such trigger scope [%s]", tr.Scope)
 	}
 
-	return true
+	return tr.validateValue()
 }
 
 // String - return rebuilt trigger string
@@ -132,6 +192,19 @@ func (tr *ParamTrigger) String() string {
 	if tr == nil {
 		return ""
 	}
-	s := fmt.Sprintf("%s%s%s", tr.On, tr.Command, tr.Scope)
+	on := tr.On
+	if tr.Value != "" || tr.hasValue {
+		on += "(" + tr.Value + ")"
+	}
+	s := fmt.Sprintf("%s%s%s", on, tr.Command, tr.Scope)
+	return s
+}
+
+// triggersString - rebuilt string of all triggers in order
+func triggersString(trs []*ParamTrigger) string {
+	var s string
+	for _, tr := range trs {
+		s += tr.String()
+	}
 	return s
 }
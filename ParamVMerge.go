@@ -3,8 +3,6 @@ package docxplate
 import (
 	"bytes"
 	"encoding/xml"
-	"slices"
-	"strings"
 )
 
 // ParamVMerge - placeholder mark to merge cell vertically
@@ -20,15 +18,7 @@ const (
 
 // isVMergeMark - does raw params part (after param key) contain ":vmerge" mark
 func isVMergeMark(raw []byte) bool {
-	raw = bytes.TrimSpace(raw)
-	raw = bytes.ToLower(raw)
-
-	// Always must start with ":"
-	if !bytes.HasPrefix(raw, []byte(":")) {
-		return false
-	}
-
-	return slices.Contains(strings.Split(string(raw[1:]), ":"), "vmerge")
+	return splitModifiers(string(raw)).vmerge
 }
 
 // applyVMerge - mark table cells holding `:vmerge` placeholders
@@ -0,0 +1,82 @@
+package docxplate
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DocxValuer - type which decides itself how it becomes a param.
+// DocxValue may return string, *Image, map[string]any, slice
+// or any other value params are collected from
+//
+//	func (m Money) DocxValue() (any, error) {
+//		return fmt.Sprintf("%.2f %s", m.Amount, m.Currency), nil
+//	}
+type DocxValuer interface {
+	DocxValue() (any, error)
+}
+
+// maxDocxValueChain - how many DocxValuer may return one another
+const maxDocxValueChain = 8
+
+var docxValuerType = reflect.TypeOf((*DocxValuer)(nil)).Elem()
+
+// docxValuer - DocxValuer of value or its pointer
+func docxValuer(val reflect.Value) (DocxValuer, bool) {
+	if !val.IsValid() {
+		return nil, false
+	}
+
+	if val.CanInterface() {
+		if dv, ok := val.Interface().(DocxValuer); ok {
+			return dv, true
+		}
+	}
+
+	// pointer receiver
+	if val.CanAddr() && val.Addr().CanInterface() {
+		if dv, ok := val.Addr().Interface().(DocxValuer); ok {
+			return dv, true
+		}
+	}
+
+	// pointer receiver of value passed by value: copy it to be addressable
+	if !val.CanAddr() && val.CanInterface() && reflect.PointerTo(val.Type()).Implements(docxValuerType) {
+		ptr := reflect.New(val.Type())
+		ptr.Elem().Set(val)
+		return ptr.Interface().(DocxValuer), true
+	}
+
+	return nil, false
+}
+
+// docxValueToParam - set param by value DocxValue returned
+func docxValueToParam(p *Param, dv DocxValuer) {
+	var v any
+	var err error
+	for i := 0; ; i++ {
+		if v, err = dv.DocxValue(); err != nil {
+			p.Type = StringParam
+			p.err = err
+			return
+		}
+
+		next, ok := v.(DocxValuer)
+		if !ok {
+			break
+		}
+		if i == maxDocxValueChain {
+			p.Type = StringParam
+			p.err = fmt.Errorf("DocxValue returns DocxValuer more than %d times", maxDocxValueChain)
+			return
+		}
+		dv = next
+	}
+
+	if v == nil {
+		p.Type = StringParam
+		return
+	}
+
+	anyToParam(p, v)
+}
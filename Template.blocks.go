@@ -0,0 +1,323 @@
+package docxplate
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Block markers: {{#if Insurance}} .. {{else}} .. {{/if}},
+// {{#each Contracts}} .. {{/each}}
+const (
+	BlockIf      = "#if"
+	BlockElse    = "else"
+	BlockIfEnd   = "/if"
+	BlockEach    = "#each"
+	BlockEachEnd = "/each"
+)
+
+// blockEnds - start marker of every end marker
+var blockEnds = map[string]string{
+	BlockIfEnd:   BlockIf,
+	BlockEachEnd: BlockEach,
+}
+
+// blockMarker - paragraph holding block marker and nothing else
+type blockMarker struct {
+	kind string // #if, else, /if, #each, /each
+	arg  string // key of #if and #each
+	text string // as in template "{{#if Insurance}}"
+	node *xmlNode
+}
+
+// block - paired markers of the same parent node,
+// everything between them belongs to block
+type block struct {
+	start, els, end *blockMarker
+}
+
+// blockMarkerOf - marker of paragraph, nil if it holds any other text too
+func (t *Template) blockMarkerOf(n *xmlNode) *blockMarker {
+	if n.Tag() != "w-p" {
+		return nil
+	}
+	match := t.delimiters().reBlock.FindSubmatch(n.AllContents())
+	if match == nil {
+		return nil
+	}
+	return &blockMarker{
+		kind: string(match[2]),
+		arg:  strings.TrimSpace(string(match[3])),
+		text: string(match[1]),
+		node: n,
+	}
+}
+
+// isBlockMarker - is placeholder like text block marker {{else}}
+func (t *Template) isBlockMarker(s string) bool {
+	return t.delimiters().reBlock.MatchString(s)
+}
+
+// blocksOf - outermost blocks of node children, nested ones are checked too.
+// Markers must be paired inside of the same parent: body, table cell..
+func (t *Template) blocksOf(n *xmlNode) ([]*block, error) {
+	var blocks, open []*block
+	for c := n.childFirst; c != nil; c = c.next {
+		m := t.blockMarkerOf(c)
+		if m == nil {
+			continue
+		}
+
+		switch m.kind {
+		case BlockIf, BlockEach:
+			if m.arg == "" {
+				return nil, t.blockError(m, fmt.Errorf("%s needs key, e.g. %s", m.kind, t.delimiters().wrap(m.kind+" Contracts")))
+			}
+			open = append(open, &block{start: m})
+		case BlockElse:
+			if len(open) == 0 || open[len(open)-1].start.kind != BlockIf || open[len(open)-1].els != nil {
+				return nil, t.blockError(m, fmt.Errorf("%s without %s", BlockElse, BlockIf))
+			}
+			open[len(open)-1].els = m
+		case BlockIfEnd, BlockEachEnd:
+			if len(open) == 0 || open[len(open)-1].start.kind != blockEnds[m.kind] {
+				return nil, t.blockError(m, fmt.Errorf("%s without %s", m.kind, blockEnds[m.kind]))
+			}
+			b := open[len(open)-1]
+			open = open[:len(open)-1]
+			b.end = m
+			if len(open) == 0 {
+				blocks = append(blocks, b)
+			}
+		}
+	}
+
+	if len(open) > 0 {
+		m := open[len(open)-1].start
+		end := BlockIfEnd
+		if m.kind == BlockEach {
+			end = BlockEachEnd
+		}
+		return nil, t.blockError(m, fmt.Errorf("%s without %s", m.kind, end))
+	}
+	return blocks, nil
+}
+
+// blockError - error of marker in current part
+func (t *Template) blockError(m *blockMarker, err error) *BlockError {
+	return &BlockError{Part: t.part, Marker: m.text, Key: m.arg, Err: err}
+}
+
+// Blocks are kept, removed or repeated with their markers as one unit:
+// paragraphs, tables and section breaks between markers.
+// Markers with errors are reported and left as they are
+func (t *Template) renderBlocks(xnode *xmlNode) {
+	if t.params == nil {
+		return
+	}
+
+	// blocks nested in kept or repeated part are the outermost ones on next round
+	var rendered bool
+	for {
+		blocks, err := t.blocksOf(xnode)
+		if err != nil {
+			t.errs = append(t.errs, err)
+			break
+		}
+		if len(blocks) == 0 {
+			break
+		}
+		for _, b := range blocks {
+			if b.start.kind == BlockEach {
+				t.renderEach(b)
+				continue
+			}
+			t.renderIf(b)
+		}
+		rendered = true
+	}
+	if rendered && xnode.Tag() == "w-tc" {
+		ensureLastParagraph(xnode)
+	}
+
+	for n := xnode.childFirst; n != nil; n = n.next {
+		if n.childFirst != nil {
+			t.renderBlocks(n)
+		}
+	}
+}
+
+// ensureLastParagraph - table cell must end with paragraph (Word reports
+// document as corrupt otherwise), empty one is added if block took it
+func ensureLastParagraph(cell *xmlNode) {
+	var last *xmlNode
+	for n := cell.childFirst; n != nil; n = n.next {
+		last = n
+	}
+	if last != nil && last.Tag() == "w-p" {
+		return
+	}
+
+	cell.insertChildAfter(last, &xmlNode{
+		XMLName: xml.Name{Local: "w-p"},
+		isNew:   true,
+	})
+}
+
+// nodes - all nodes of block, markers too
+func (b *block) nodes() []*xmlNode {
+	var nodes []*xmlNode
+	for n := b.start.node; n != nil; n = n.next {
+		nodes = append(nodes, n)
+		if n == b.end.node {
+			break
+		}
+	}
+	return nodes
+}
+
+// renderIf - keep part of block by value of key, unknown key is false
+// and is reported as unresolved (see MissingKeyFail)
+func (t *Template) renderIf(b *block) {
+	p := t.params.lookupKey("", b.start.arg)
+	if p == nil {
+		t.unresolvedMarker(b.start)
+	}
+	b.render(truthy(p))
+}
+
+// unresolvedMarker - collect marker of unknown key as unresolved placeholder
+func (t *Template) unresolvedMarker(m *blockMarker) {
+	if t.missingKey != MissingKeyFail {
+		return
+	}
+	t.unresolved = append(t.unresolved, UnresolvedPlaceholder{
+		Placeholder: m.text,
+		Key:         m.arg,
+		Location:    t.locate(t.part, m.node),
+	})
+}
+
+// render - remove markers and part of block not taken
+func (b *block) render(keepIf bool) {
+	keepAny := keepIf || b.els != nil
+	nodes := b.nodes()
+
+	inIf := true
+	for _, n := range nodes {
+		switch {
+		case n == b.start.node || n == b.end.node:
+			deleteMarker(n, keepAny)
+		case b.els != nil && n == b.els.node:
+			inIf = false
+			deleteMarker(n, keepAny)
+		case inIf != keepIf:
+			n.delete()
+		}
+	}
+}
+
+// deleteMarker - remove marker paragraph, section break it holds
+// stays in empty paragraph when content around is kept
+func deleteMarker(n *xmlNode, keepBreak bool) {
+	var hasBreak bool
+	n.Walk(func(n2 *xmlNode) {
+		hasBreak = hasBreak || n2.Tag() == "w-sectPr"
+	})
+	if !hasBreak || !keepBreak {
+		n.delete()
+		return
+	}
+
+	for c := n.childFirst; c != nil; c = c.next {
+		if c.Tag() != "w-pPr" {
+			c.delete()
+		}
+	}
+}
+
+// renderEach - repeat content between markers for every item of slice,
+// relative keys {{.Name}} of it become keys of item {{Contracts.1.Name}}.
+// Block of null slice is removed, of unknown key is removed and reported
+// as unresolved (see MissingKeyFail), of any other value as *BlockError
+func (t *Template) renderEach(b *block) {
+	var items ParamList
+	p := t.params.findByKey(b.start.arg)
+	switch {
+	case p == nil:
+		t.unresolvedMarker(b.start)
+	case p.Type == SliceParam:
+		items = p.Params
+	case p.State() != ParamStateNull:
+		t.errs = append(t.errs, t.blockError(b.start, fmt.Errorf("key [%s] is not a slice", b.start.arg)))
+	}
+
+	nodes := b.nodes()
+	body := nodes[1 : len(nodes)-1]
+	parent := b.start.node.parent
+
+	mark := b.end.node.priv
+	for _, item := range items {
+		var copies []*xmlNode
+		for _, n := range body {
+			ncopy := n.copyTree(parent)
+			parent.insertChildAfter(mark, ncopy)
+			mark = ncopy
+			copies = append(copies, ncopy)
+		}
+		t.relativeTo(copies, item.AbsoluteKey)
+	}
+
+	for _, n := range body {
+		n.delete()
+	}
+	deleteMarker(b.start.node, len(items) > 0)
+	deleteMarker(b.end.node, len(items) > 0)
+}
+
+// relativeTo - relative keys {{.Name}}, {{#if .Insured}}.. of nodes
+// become keys of item {{Contracts.1.Name}}. Nested {{#each}} keep theirs
+func (t *Template) relativeTo(nodes []*xmlNode, item string) {
+	d := t.delimiters()
+	rewrite := func(buf []byte) []byte {
+		return d.reRelative.ReplaceAllFunc(buf, func(match []byte) []byte {
+			sub := d.reRelative.FindSubmatch(match)
+			if len(sub[2]) == 0 {
+				return []byte(string(sub[1]) + item) // {{.}} - item itself
+			}
+			return []byte(string(sub[1]) + item + "." + string(sub[2]))
+		})
+	}
+
+	var depth int
+	visit := func(n *xmlNode) {
+		if m := t.blockMarkerOf(n); m != nil {
+			switch m.kind {
+			case BlockEach:
+				// key of nested each is relative to this item, its content is not
+				if depth == 0 {
+					n.Walk(func(n2 *xmlNode) {
+						n2.Content = rewrite(n2.Content)
+					})
+				}
+				depth++
+			case BlockEachEnd:
+				depth--
+			}
+			return
+		}
+		if depth > 0 {
+			return
+		}
+
+		for i := range n.Attrs {
+			n.Attrs[i].Value = string(rewrite([]byte(n.Attrs[i].Value)))
+		}
+		n.Content = rewrite(n.Content)
+	}
+
+	for _, n := range nodes {
+		visit(n)
+		n.Walk(visit)
+	}
+}
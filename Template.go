@@ -4,6 +4,7 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -11,6 +12,8 @@ import (
 	"path"
 	"reflect"
 	"strings"
+
+	"golang.org/x/text/language"
 )
 
 const mainDocFname = "word/document.xml"
@@ -22,9 +25,11 @@ var modFileNamesLike = []string{
 	mainDocFname,
 	"word/header",
 }
-var t *Template
 
 // Template ..
+// All render state (params, modified and added files, images) belongs to
+// the template instance, so separate templates can be rendered in parallel.
+// A single template must not be used from multiple goroutines at once.
 type Template struct {
 	path string
 	// file *os.File
@@ -44,17 +49,40 @@ type Template struct {
 
 	// hold all parsed params:values here
 	params ParamList
+
+	// options given when opening template
+	opts []Option
+	// error of last applied options
+	optionErr error
+
+	// placeholder delimiters
+	delims *delimiters
+	// what to do with placeholders left without value
+	missingKey MissingKey
+	// locale of number formats
+	locale language.Tag
+	// :empty trigger fires only on empty value, not on unknown and null keys
+	strictEmpty bool
+	// custom formatters of this template only
+	formatters map[string]FormatFunc
+
+	// part (document, header1, footer2..) being rendered now
+	part string
+	// errors collected while rendering
+	errs []error
+	// placeholders left without value (MissingKeyFail only)
+	unresolved []UnresolvedPlaceholder
 }
 
 // OpenTemplate - docpath local file
-func OpenTemplate(docpath string) (*Template, error) {
+func OpenTemplate(docpath string, opts ...Option) (*Template, error) {
 	var err error
 	docBytes, err := os.ReadFile(docpath) // #nosec G304 - allowed filename variable here
 	if err != nil {
 		return nil, err
 	}
 
-	t, err := OpenTemplateWithBytes(docBytes)
+	t, err := OpenTemplateWithBytes(docBytes, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -66,15 +94,21 @@ func OpenTemplate(docpath string) (*Template, error) {
 
 // OpenTemplateWithBytes - template from bytes
 // Credits to @dreamph for implementing this function
-func OpenTemplateWithBytes(docBytes []byte) (*Template, error) {
+func OpenTemplateWithBytes(docBytes []byte, opts ...Option) (*Template, error) {
 	var err error
 
 	// Init doc template
-	t = &Template{
+	t := &Template{
 		files:        map[string]*zip.File{},
 		documentRels: map[string]*zip.File{},
 		added:        map[string][]byte{},
 		modified:     map[string][]byte{},
+		opts:         opts,
+		delims:       defaultDelims,
+	}
+
+	if err := t.applyOptions(nil); err != nil {
+		return nil, err
 	}
 
 	// Unzip
@@ -106,7 +140,7 @@ func OpenTemplateWithBytes(docBytes []byte) (*Template, error) {
 }
 
 // OpenTemplateWithURL .. docpath is remote url
-func OpenTemplateWithURL(docurl string) (tpl *Template, err error) {
+func OpenTemplateWithURL(docurl string, opts ...Option) (tpl *Template, err error) {
 	docpath, err := DefaultDownloader.DownloadFile(context.Background(), docurl)
 	if err != nil {
 		return nil, err
@@ -118,7 +152,7 @@ func OpenTemplateWithURL(docurl string) (tpl *Template, err error) {
 		}
 	}()
 
-	tpl, err = OpenTemplate(docpath)
+	tpl, err = OpenTemplate(docpath, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -134,61 +168,185 @@ func OpenTemplateWithURL(docurl string) (tpl *Template, err error) {
 
 // Params  - replace template placeholders with params
 // "Hello {{ Name }}!"" --> "Hello World!""
+// Errors are only logged, use Render to get them
 func (t *Template) Params(v any) {
-	// t.params = collectParams("", v)
+	if err := t.Render(v); err != nil {
+		log.Printf("Params: %s", err)
+	}
+}
+
+// Render - replace template placeholders with params (same as Params)
+// and return errors found while doing it.
+// Parts with errors are rendered as much as possible.
+// Check returned error with errors.As for
+// *ParamsError, *PartError, *ImageError, *TriggerError, *BlockError, *UnresolvedError
+func (t *Template) Render(v any, opts ...Option) error {
+	if err := t.applyOptions(opts); err != nil {
+		return err
+	}
+	t.errs = nil
+	t.unresolved = nil
+
+	params, err := collectParams(v)
+	if err != nil {
+		return err
+	}
+	t.setParams(params)
+
+	for _, f := range t.files {
+		if !isModFile(f.Name) {
+			continue
+		}
+
+		xnode, err := t.fileToXMLStruct(f.Name)
+		if err != nil {
+			t.errs = append(t.errs, err)
+			continue
+		}
+		t.prepareXML(xnode)
+		t.renderXML(f.Name, xnode)
+	}
+
+	return t.renderErr()
+}
+
+// all errors of render as one
+func (t *Template) renderErr() error {
+	errs := t.errs
+	if len(t.unresolved) > 0 {
+		errs = append(errs, &UnresolvedError{Placeholders: t.unresolved})
+	}
+	return errors.Join(errs...)
+}
+
+// set params to replace, they must know template delimiters.
+// Params which values failed are left out and reported
+func (t *Template) setParams(params ParamList) {
+	params, errs := params.dropErrors()
+	t.errs = append(t.errs, errs...)
+	if params == nil {
+		params = ParamList{} // rendered without params, all keys are unknown
+	}
+
+	// {{Friends.#number}}.. and their keys
+	params.setLoopMeta()
+	params.Walk(func(p *Param) {
+		// use Walk func built-in logic to assign keys
+	})
+
+	params.WalkWithEnd(func(p *Param) bool {
+		p.delims = t.delims
+		return false
+	})
+	t.params = params
+}
+
+// delimiters of template placeholders
+func (t *Template) delimiters() *delimiters {
+	if t.delims == nil {
+		return defaultDelims
+	}
+	return t.delims
+}
+
+// collect params from any supported type of input
+func collectParams(v any) (ParamList, error) {
 	switch val := v.(type) {
 	case map[string]any:
-		t.params = mapToParams(val)
+		params := mapToParams(val)
+		params.Walk(func(p *Param) {
+			// use Walk func built-in logic to assign keys
+		})
+		return params, nil
 	case string:
-		t.params = JSONToParams([]byte(val))
+		return jsonToParams([]byte(val))
 	case []byte:
-		t.params = JSONToParams(val)
+		return jsonToParams(val)
 	default:
-		if reflect.ValueOf(v).Kind() == reflect.Struct {
-			t.params = StructToParams(val)
-		} else {
-			// any other type try to convert
-			t.params = AnyToParams(val)
+		// structs, pointers to them and maps of other types by reflect,
+		// so docx tags, DocxValuer and typed values are kept however passed
+		if rv := indirect(reflect.ValueOf(v)); rv.Kind() == reflect.Struct || rv.Kind() == reflect.Map {
+			root := NewParam("")
+			reflectToParam(root, rv)
+			if root.err != nil {
+				return nil, &ParamsError{Err: root.err}
+			}
+			if root.Type == StructParam {
+				params := root.Params
+				params.Walk(func(p *Param) {
+					// use Walk func built-in logic to assign keys
+				})
+				return params, nil
+			}
 		}
+		// any other type try to convert
+		return anyToParams(val)
 	}
+}
 
-	for _, f := range t.files {
-		for _, keyword := range modFileNamesLike {
-			if !strings.Contains(f.Name, keyword) {
-				continue
-			}
+// is file allowed to check/modify for params
+func isModFile(fname string) bool {
+	for _, keyword := range modFileNamesLike {
+		if strings.Contains(fname, keyword) {
+			return true
+		}
+	}
+	return false
+}
 
-			xnode := t.fileToXMLStruct(f.Name)
+// Prepare parsed file for params replace.
+// Does not depend on params so can be done once per template
+func (t *Template) prepareXML(xnode *xmlNode) {
+	// Enhance some markup (removed when building XML in the end)
+	// so easier to find some element
+	t.enhanceMarkup(xnode)
 
-			// Enhance some markup (removed when building XML in the end)
-			// so easier to find some element
-			t.enhanceMarkup(xnode)
+	// While formating docx sometimes same style node is split to
+	// multiple same style nodes and different content
+	// Merge them so placeholders are in the same node
+	t.fixBrokenPlaceholders(xnode)
 
-			// While formating docx sometimes same style node is split to
-			// multiple same style nodes and different content
-			// Merge them so placeholders are in the same node
-			t.fixBrokenPlaceholders(xnode)
+	// Escaped delimiters `\{{Key}}` are text, hide them from placeholder search
+	t.escapeDelimiters(xnode)
+}
 
-			// Complex placeholders with more depth needs to be expanded
-			// for correct replace
-			t.expandPlaceholders(xnode)
+// Replace params in prepared file and save it as modified
+func (t *Template) renderXML(fname string, xnode *xmlNode) {
+	t.part = partName(fname)
 
-			// Replace params
-			t.replaceSingleParams(xnode, false)
+	// Report invalid triggers, these placeholders are replaced as usual
+	t.checkTriggers(xnode)
 
-			// Collect placeholders with trigger but unset in `t.params`
-			// Placeholders with trigger `:empty` must be triggered
-			// otherwise they are left
-			t.triggerMissingParams(xnode)
+	// Blocks {{#if Key}}..{{/if}} are kept or removed as whole
+	t.renderBlocks(xnode)
 
-			// After all done with placeholders, modify contents
-			// - new lines to docx new lines
-			t.enhanceContent(xnode)
+	// Complex placeholders with more depth needs to be expanded
+	// for correct replace
+	t.expandPlaceholders(xnode)
 
-			// Save []bytes
-			t.modified[f.Name] = structToXMLBytes(xnode)
-		}
-	}
+	// Directives leave no text, only run their triggers
+	t.runDirectives(xnode, t.params)
+
+	// Replace params
+	t.replaceSingleParams(xnode, t.params, t.params, false)
+
+	// Collect placeholders with trigger but unset in `t.params`
+	// Placeholders with trigger `:empty` must be triggered
+	// otherwise they are left
+	t.triggerMissingParams(xnode)
+
+	// Placeholders still left with default text get it
+	t.replaceDefaults(xnode)
+
+	// Placeholders still left are handled by missing key policy
+	t.handleMissingKeys(xnode)
+
+	// After all done with placeholders, modify contents
+	// - new lines to docx new lines
+	t.enhanceContent(xnode)
+
+	// Save []bytes
+	t.modified[fname] = structToXMLBytes(xnode)
 }
 
 // Bytes - create docx archive but return only bytes of it
@@ -225,6 +383,7 @@ func (t *Template) Bytes() ([]byte, error) {
 
 		// Move/Write struct-saved file to docx archive file back
 		if buf, isModified := t.modified[f.Name]; isModified {
+			buf = t.delimiters().unescape(buf)
 			if _, err := fw.Write(buf); err != nil {
 				log.Printf("[%s] write error: %s", f.Name, err)
 			}
@@ -265,26 +424,44 @@ func (t *Template) ExportDocx(path string) error {
 // Placeholders - get list of used params placeholders in template
 // If you already replaced params with values then you will not get all placeholders.
 // Or use it after replace and see how many placeholders left.
+// See Inspect for placeholders with their details and locations.
 func (t *Template) Placeholders() []string {
 	var arr []string
 
-	plaintext := t.Plaintext()
+	plaintext := t.escapedPlaintext()
 
-	arr = reParamExtract.FindAllString(plaintext, -1)
+	arr = t.delimiters().reParam.FindAllString(plaintext, -1)
 
 	return arr
 }
 
 // Plaintext - return as plaintext
 func (t *Template) Plaintext() string {
+	return string(t.delimiters().unescape([]byte(t.escapedPlaintext())))
+}
 
-	if len(t.params) == 0 {
-		// if params not set yet we init process with empty params
-		// and mark content as changed so we can return plaintext with placeholders
-		// not replaced yet
-		t.Params(nil)
+// escapedPlaintext - plaintext with escaped delimiters still hidden
+func (t *Template) escapedPlaintext() string {
+
+	if len(t.modified) == 0 {
+		// if not rendered yet we prepare parts of a copy without rendering
+		// them, so we can return plaintext with placeholders as written
+		// (no trigger or block run on missing keys) and template itself stays untouched
+		tcopy, parts, err := t.preparedParts()
+		if err != nil {
+			log.Printf("Plaintext: %s", err)
+		}
+		for fname, xnode := range parts {
+			tcopy.modified[fname] = structToXMLBytes(xnode)
+		}
+		return tcopy.plaintext()
 	}
 
+	return t.plaintext()
+}
+
+// plaintext of modified files
+func (t *Template) plaintext() string {
 	plaintext := ""
 
 	// for fpath, f := range t.files {
@@ -297,8 +474,12 @@ func (t *Template) Plaintext() string {
 	// }
 
 	// header and footer must be printed in plaintext
-	for _, f := range t.modified {
-		xnode := t.bytesToXMLStruct(f)
+	for fname, f := range t.modified {
+		xnode, err := t.bytesToXMLStruct(f)
+		if err != nil {
+			log.Printf("[%s] plaintext: %s", fname, err)
+			continue
+		}
 
 		xnode.Walk(func(n *xmlNode) {
 			if n.Tag() != "w-p" {
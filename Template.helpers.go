@@ -3,12 +3,12 @@ package docxplate
 import (
 	"bytes"
 	"encoding/xml"
-	"log"
+	"errors"
 	"strings"
 )
 
 // Convert given bytes to struct of xml nodes
-func (t *Template) bytesToXMLStruct(buf []byte) *xmlNode {
+func (t *Template) bytesToXMLStruct(buf []byte) (*xmlNode, error) {
 	// encoding/xml resolves name prefixes to namespace URIs and
 	// invents new prefixes on marshal (w:val -> main:val etc).
 	// Encode ":" inside tag names as "-" so original prefixes
@@ -21,7 +21,7 @@ func (t *Template) bytesToXMLStruct(buf []byte) *xmlNode {
 
 	xdocNode := &xmlNode{}
 	if err := xml.Unmarshal(buf, xdocNode); err != nil {
-		log.Printf("fileToXMLStruct: %v", err)
+		return xdocNode, err
 	}
 
 	xdocNode.FixNamespaceDuplication()
@@ -42,20 +42,31 @@ func (t *Template) bytesToXMLStruct(buf []byte) *xmlNode {
 	})
 
 	// log.Printf("%s", structToXMLBytes(n))
-	return xdocNode
+	return xdocNode, nil
 }
 
 // Convert given file (from template.Files) to struct of xml nodes
-func (t *Template) fileToXMLStruct(fname string) *xmlNode {
+func (t *Template) fileToXMLStruct(fname string) (*xmlNode, error) {
 	f, ok := t.files[fname]
 	if !ok {
-		return nil
+		return nil, &PartError{Part: partName(fname), Err: errors.New("not found")}
 	}
 
-	fr, _ := f.Open()
-	buf := readerBytes(fr)
+	fr, err := f.Open()
+	if err != nil {
+		return nil, &PartError{Part: partName(fname), Err: err}
+	}
+
+	buf, err := readAllAndClose(fr)
+	if err != nil {
+		return nil, &PartError{Part: partName(fname), Err: err}
+	}
 
-	return t.bytesToXMLStruct(buf)
+	xnode, err := t.bytesToXMLStruct(buf)
+	if err != nil {
+		return nil, &PartError{Part: partName(fname), Err: err}
+	}
+	return xnode, nil
 }
 
 // wrapper for simple param replace func
@@ -63,6 +74,14 @@ func (t *Template) replaceTextParam(xnode *xmlNode, param *Param) {
 	xnode.Content = param.replaceIn(xnode.Content)
 }
 
+// Text placeholder replace with value only for this placeholder, param is reused
+func (t *Template) replaceTextParamWith(xnode *xmlNode, param *Param, value string) {
+	paramValue := param.Value
+	param.Value = value
+	t.replaceTextParam(xnode, param)
+	param.Value = paramValue
+}
+
 // Image placeholder replace
 func (t *Template) replaceImageParams(xnode *xmlNode, param *Param) {
 	// Sometime the placeholder is in the before or middle of the text, but node is appended in the last.
@@ -81,7 +100,11 @@ func (t *Template) replaceImageParams(xnode *xmlNode, param *Param) {
 		}
 		// image node
 		if len(contentSlice)-i > 1 {
-			imgNode := t.bytesToXMLStruct([]byte(param.Value))
+			imgNode, err := t.bytesToXMLStruct([]byte(param.Value))
+			if err != nil {
+				t.errs = append(t.errs, &ImageError{Part: t.part, Key: param.AbsoluteKey, Err: err})
+				continue
+			}
 			imgNode.parent = xnode.parent
 			xnode.add(imgNode)
 		}
@@ -93,18 +116,39 @@ func (t *Template) replaceImageParams(xnode *xmlNode, param *Param) {
 
 // Check for broken placeholders
 func (t *Template) matchBrokenPlaceholder(content string, isLeft bool) bool {
+	d := t.delimiters()
 	stack := 0
+	escaped := 0 // escaped open delimiters, they close as text too
 
-	for i := 1; i < len(content); i++ {
-		if content[i] == '{' && content[i-1] == '{' {
+	for i := 0; i < len(content); i++ {
+		if strings.HasPrefix(content[i:], escapeMark+d.open) {
+			escaped++
+			i += len(escapeMark+d.open) - 1
+			continue
+		}
+		if strings.HasPrefix(content[i:], escapeChar+escapeChar) {
+			i += len(escapeChar+escapeChar) - 1 // escapeChar of text
+			continue
+		}
+		if strings.HasPrefix(content[i:], escapedOpen) {
+			escaped++
+			i += len(escapedOpen) - 1
+			continue
+		}
+		if strings.HasPrefix(content[i:], d.open) {
 			stack++
-			i++ // Skip next character
+			i += len(d.open) - 1 // Skip rest of delimiter
 			continue
 		}
-		if content[i] == '}' && content[i-1] == '}' {
+		if strings.HasPrefix(content[i:], d.close) {
 			if stack > 0 {
 				stack--
-				i++ // Skip next character
+				i += len(d.close) - 1 // Skip rest of delimiter
+				continue
+			}
+			if escaped > 0 {
+				escaped--
+				i += len(d.close) - 1
 				continue
 			}
 
@@ -122,6 +166,28 @@ func (t *Template) matchBrokenLeftPlaceholder(content string) bool {
 	return t.matchBrokenPlaceholder(content, true)
 }
 
+// matchSplitOpenDelimiter - open delimiter itself split between nodes: "[" + "[Key]]"
+// or escape mark split from it: "\\" + "{{Key}}".
+// Content must end with part of delimiter and next node go on with the rest of it
+func (t *Template) matchSplitOpenDelimiter(content, next string) bool {
+	d := t.delimiters()
+	if strings.HasSuffix(content, escapeMark) && continuesWith(next, d.open) {
+		return true
+	}
+	for i := 1; i < len(d.open); i++ {
+		if strings.HasSuffix(content, d.open[:i]) && continuesWith(next, d.open[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// continuesWith - next node text starts with rest of delimiter,
+// or is itself part of it when delimiter is split more times: "[" + "[" + "Key]]"
+func continuesWith(next, rest string) bool {
+	return next != "" && (strings.HasPrefix(next, rest) || strings.HasPrefix(rest, next))
+}
+
 // UNUSED
 // // Match right placeholder part `}}`
 // func (t *Template) matchBrokenRightPlaceholder(content string) bool {
@@ -129,27 +195,30 @@ func (t *Template) matchBrokenLeftPlaceholder(content string) bool {
 // }
 
 // GetAttrParam - extracts and returns substrings enclosed in double curly braces "{{...}}" from the given string
+// (or other delimiters of template, see WithDelimiters)
 func (t Template) GetAttrParam(attr string) []string {
-	var ret []string
-	var record strings.Builder
-	start := false
-	length := len(attr)
-	for i := 1; i < length-1; i++ {
+	d := t.delimiters()
 
-		if attr[i] == '{' && attr[i-1] == '{' {
-			start = true
-			continue
+	var ret []string
+	for {
+		start := strings.Index(attr, d.open)
+		if start < 0 {
+			break
 		}
+		attr = attr[start+len(d.open):]
 
-		if start && (attr[i] == ' ' || (attr[i] == '}' && length-1 > i && attr[i+1] == '}')) {
-			ret = append(ret, record.String())
-			record.Reset()
-			start = false
+		end := strings.Index(attr, d.close)
+		if end < 0 {
+			break
 		}
 
-		if start {
-			record.WriteByte(attr[i])
+		// key ends with space (params, separator follows) or close delimiter
+		key := attr[:end]
+		if i := strings.IndexByte(key, ' '); i >= 0 {
+			key = key[:i]
 		}
+		ret = append(ret, key)
+		attr = attr[end+len(d.close):]
 	}
 
 	return ret
@@ -0,0 +1,232 @@
+package docxplate
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ExpandKind - how placeholder is expanded, as told by placeholder, its row
+// and sample data if given: {{Nicknames}} is single, row if its value is a slice
+type ExpandKind int8
+
+// Expand kinds
+const (
+	ExpandSingle  ExpandKind = iota // key without dot {{Name}}, attribute or indexed key {{Friends.1.Name}}
+	ExpandInline                    // slice values joined by separator {{Nicknames , }}
+	ExpandRow                       // key of slice item: paragraph or table row multiplied for every item {{Friends.#number}}, {{Friends.Name}} next to it or of sample data slice
+	ExpandUnknown                   // dotted key: struct field {{Address.Street}} or row of slice items {{Friends.Name}}, known with data only
+)
+
+// String - kind as human readable text
+func (kind ExpandKind) String() string {
+	switch kind {
+	case ExpandInline:
+		return "inline"
+	case ExpandRow:
+		return "row"
+	case ExpandUnknown:
+		return "unknown"
+	default:
+		return "single"
+	}
+}
+
+// PlaceholderInfo - placeholder found in template
+type PlaceholderInfo struct {
+	Placeholder string // as in template "{{Friends.Name :empty:remove:row}}"
+
+	// Parsed the same way as by NewParamFromRaw
+	Key       string
+	Separator string
+	Trigger   *ParamTrigger // first of Triggers
+	Triggers  []*ParamTrigger
+	Formatter *ParamFormatter
+	VMerge    bool
+	Directive bool // {{?Key :remove:row}} leaves no text
+
+	Location
+	Expand ExpandKind
+}
+
+// reIndexedKey - key holding slice index "Friends.1.Name"
+var reIndexedKey = regexp.MustCompile(`(^|\.)\d+(\.|$)`)
+
+// Inspect - list all placeholders of template with their locations.
+// Dotted keys are told row or single by sample data if given: tdoc.Inspect(user).
+// Template is not rendered or changed in any way
+func (t *Template) Inspect(sample ...any) ([]PlaceholderInfo, error) {
+	params, err := sampleParams(sample)
+	if err != nil {
+		return nil, err
+	}
+	tcopy, parts, err := t.preparedParts()
+	if err != nil {
+		return nil, err
+	}
+	return tcopy.inspectParts(parts, params), nil
+}
+
+// sampleParams - params of sample data of Inspect, nil if there is none
+func sampleParams(sample []any) (ParamList, error) {
+	if len(sample) == 0 || sample[0] == nil {
+		return nil, nil
+	}
+	params, err := collectParams(sample[0])
+	if err != nil {
+		return nil, err
+	}
+	params, _ = params.dropErrors()
+	if params == nil {
+		params = ParamList{} // no keys known, not no data
+	}
+	params.setLoopMeta()
+	params.Walk(func(p *Param) {
+		// use Walk func built-in logic to assign keys
+	})
+	return params, nil
+}
+
+// preparedParts - parsed and prepared files holding placeholders and copy of
+// template they are prepared with. Copy has options of template, not of the last
+// render, and template itself keeps options of the last render for its document
+func (t *Template) preparedParts() (*Template, map[string]*xmlNode, error) {
+	tcopy := t.renderCopy()
+	if err := tcopy.applyOptions(nil); err != nil {
+		return tcopy, nil, err
+	}
+
+	parts := map[string]*xmlNode{}
+	for _, f := range tcopy.files {
+		if !isModFile(f.Name) {
+			continue
+		}
+
+		xnode, err := tcopy.fileToXMLStruct(f.Name)
+		if err != nil {
+			return tcopy, nil, err
+		}
+		tcopy.prepareXML(xnode)
+		parts[f.Name] = xnode
+	}
+	return tcopy, parts, nil
+}
+
+// Inspect - list all placeholders of compiled template with their locations
+func (ct *CompiledTemplate) Inspect() []PlaceholderInfo {
+	return ct.t.inspectParts(ct.parts, nil)
+}
+
+// inspectParts - placeholders of all given parts, dotted keys told by sample
+// params if not nil. Parts sorted by name, placeholders of a part in document order
+func (t *Template) inspectParts(parts map[string]*xmlNode, sample ParamList) []PlaceholderInfo {
+	var list []PlaceholderInfo
+	for _, fname := range sortedPartNames(parts) {
+		part := partName(fname)
+		parts[fname].Walk(func(n *xmlNode) {
+			for _, attr := range n.Attrs {
+				for _, info := range t.inspectContent([]byte(attr.Value)) {
+					info.Location = Location{Part: part, Kind: LocationAttribute, Text: attr.Value}
+					info.Expand = ExpandSingle
+					list = append(list, info)
+				}
+			}
+
+			if len(n.Content) == 0 {
+				return
+			}
+			for _, info := range t.inspectContent(n.Content) {
+				info.Location = t.locate(part, n)
+				if info.Expand == ExpandUnknown {
+					info.Expand = t.dottedExpand(info.Key, rowOf(n), sample)
+				}
+				list = append(list, info)
+			}
+		})
+	}
+
+	return list
+}
+
+// inspectContent - placeholders of given text
+func (t *Template) inspectContent(buf []byte) []PlaceholderInfo {
+	var list []PlaceholderInfo
+	for _, match := range t.delimiters().reParam.FindAllSubmatch(buf, -1) {
+		p := newParamFromRaw(match[0], t.delimiters())
+		if p == nil || t.isBlockMarker(string(match[0])) {
+			continue
+		}
+
+		info := PlaceholderInfo{
+			Placeholder: string(match[0]),
+			Key:         strings.TrimSpace(p.Key),
+			Separator:   p.Separator,
+			Trigger:     p.Trigger,
+			Triggers:    p.Triggers,
+			Formatter:   p.Formatter,
+			VMerge:      p.VMerge,
+			Directive:   p.Directive,
+			Expand:      ExpandUnknown,
+		}
+		switch {
+		case len(match[3]) > 0:
+			info.Expand = ExpandInline
+		case reIndexedKey.MatchString(info.Key), !strings.Contains(info.Key, "."):
+			info.Expand = ExpandSingle
+		case strings.Contains(info.Key, ".#"):
+			info.Expand = ExpandRow
+		}
+		list = append(list, info)
+	}
+	return list
+}
+
+// dottedExpand - kind of dotted key by sample params if there are any,
+// else by loop key of the same slice in its row:
+// {{Friends.Name}} next to {{Friends.#number}} is row
+func (t *Template) dottedExpand(key string, nrow *xmlNode, sample ParamList) ExpandKind {
+	if sample != nil {
+		switch {
+		case len(sample.slicesOf(key)) > 0:
+			return ExpandRow
+		case sample.findByKey(key) != nil:
+			return ExpandSingle
+		}
+	}
+
+	if nrow == nil {
+		return ExpandUnknown
+	}
+	for _, info := range t.inspectContent(nrow.AllContents()) {
+		if slice, _, ok := strings.Cut(info.Key, ".#"); ok && strings.HasPrefix(key, slice+".") {
+			return ExpandRow
+		}
+	}
+	return ExpandUnknown
+}
+
+// rowOf - table row or paragraph node is in, the one expanded for slice items
+func rowOf(xnode *xmlNode) *xmlNode {
+	var nrow *xmlNode
+	for n := xnode; n != nil; n = n.parent {
+		switch n.Tag() {
+		case "w-tr":
+			return n
+		case "w-p":
+			if nrow == nil {
+				nrow = n
+			}
+		}
+	}
+	return nrow
+}
+
+// sortedPartNames - file names of parts sorted
+func sortedPartNames(parts map[string]*xmlNode) []string {
+	var fnames []string
+	for fname := range parts {
+		fnames = append(fnames, fname)
+	}
+	sort.Strings(fnames)
+	return fnames
+}
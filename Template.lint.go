@@ -0,0 +1,263 @@
+package docxplate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity - how bad is lint diagnostic, the worse the greater:
+// d.Severity >= SeverityWarning
+type Severity int8
+
+// Severities
+const (
+	SeverityInfo    Severity = iota // hint, depends on data given to render
+	SeverityWarning                 // template works, but maybe not as expected
+	SeverityError                   // placeholder will not work
+)
+
+// String - severity as human readable text
+func (sev Severity) String() string {
+	switch sev {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// Diagnostic - single lint finding
+type Diagnostic struct {
+	Severity    Severity
+	Message     string
+	Placeholder string // placeholder or text it's about
+	Location
+}
+
+// String - "error: document: paragraph "Hi {{Name :uper}}": unknown formatter [:uper]"
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s", d.Severity, d.Location, d.Message)
+}
+
+// Lint - check template for broken or invalid placeholders.
+// Template is not rendered or changed in any way
+func (t *Template) Lint() ([]Diagnostic, error) {
+	tcopy, parts, err := t.preparedParts()
+	if err != nil {
+		return nil, err
+	}
+	return tcopy.lintParts(parts), nil
+}
+
+// Lint - check compiled template for broken or invalid placeholders
+func (ct *CompiledTemplate) Lint() []Diagnostic {
+	return ct.t.lintParts(ct.parts)
+}
+
+// lintParts - diagnostics of all given parts
+func (t *Template) lintParts(parts map[string]*xmlNode) []Diagnostic {
+	var list []Diagnostic
+
+	// placeholder level checks
+	for _, info := range t.inspectParts(parts, nil) {
+		list = append(list, t.lintPlaceholder(info)...)
+	}
+
+	// paragraph and row level checks
+	for _, fname := range sortedPartNames(parts) {
+		part := partName(fname)
+		parts[fname].WalkWithEnd(func(nrow *xmlNode) bool {
+			if !nrow.isRowElement() {
+				return false
+			}
+			list = append(list, t.lintRow(part, nrow)...)
+			return true
+		})
+	}
+
+	// block markers pairing
+	for _, fname := range sortedPartNames(parts) {
+		list = append(list, t.lintBlocks(partName(fname), parts[fname])...)
+	}
+
+	// by part, in order found
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Part < list[j].Part
+	})
+
+	return list
+}
+
+// lintPlaceholder - check placeholder params part: triggers, formatters, slice modifiers, vmerge
+func (t *Template) lintPlaceholder(info PlaceholderInfo) []Diagnostic {
+	var list []Diagnostic
+	add := func(sev Severity, format string, args ...any) {
+		list = append(list, Diagnostic{
+			Severity:    sev,
+			Message:     fmt.Sprintf(format, args...),
+			Placeholder: info.Placeholder,
+			Location:    info.Location,
+		})
+	}
+
+	raw := t.paramsPart(info.Placeholder)
+	if !hasModifiers(raw) {
+		return nil
+	}
+
+	// words of neither trigger, vmerge mark nor known formatter
+	// are typos of formatter or, when trigger is there, trigger word
+	hasTrigger := len(splitModifiers(raw).trigger) > 0
+	var triggerTypo bool
+	if info.Formatter != nil {
+		for _, step := range info.Formatter.Steps {
+			switch {
+			case !step.known(t.formatters) && hasTrigger:
+				add(SeverityError, "unknown trigger word [%s]", step.Format)
+				triggerTypo = true
+			case !step.known(t.formatters):
+				add(SeverityError, "unknown formatter [%s]", step.Format)
+			default:
+				if err := step.validate(); err != nil {
+					add(SeverityError, "invalid formatter [%s]: %s", step, err)
+				}
+			}
+		}
+	}
+
+	// trigger left without its typo word is invalid for that reason only
+	if _, err := placeholderTriggers(info.Directive, []byte(raw)); err != nil && !triggerTypo {
+		add(SeverityError, "invalid trigger [%s]: %s", raw, err)
+	}
+
+	for _, m := range splitModifiers(raw).slice {
+		if err := m.validateSlice(); err != nil {
+			add(SeverityError, "invalid slice modifier [%s]: %s", m, err)
+		}
+	}
+
+	if info.VMerge && info.Kind != LocationTableCell {
+		add(SeverityWarning, "%s outside of table row has no effect", ParamVMerge)
+	}
+
+	return list
+}
+
+// paramsPart - ":empty:remove:row" part of placeholder
+func (t *Template) paramsPart(placeholder string) string {
+	match := t.delimiters().reParam.FindStringSubmatch(placeholder)
+	if match == nil {
+		return ""
+	}
+	return strings.TrimSpace(match[4])
+}
+
+// lintRow - check paragraph or table row: unbalanced braces,
+// text looking like placeholder but not one, keys from different slices
+func (t *Template) lintRow(part string, nrow *xmlNode) []Diagnostic {
+	var list []Diagnostic
+
+	contents := string(nrow.AllContents())
+	loc := Location{Part: part, Kind: LocationParagraph, Text: string(t.delimiters().unescape([]byte(contents)))}
+	if nrow.Tag() == "w-tr" {
+		loc.Kind = LocationTableCell
+	} else if isListItem, _ := nrow.IsListItem(); isListItem {
+		loc.Kind = LocationListItem
+	}
+	add := func(sev Severity, placeholder, format string, args ...any) {
+		list = append(list, Diagnostic{
+			Severity:    sev,
+			Message:     fmt.Sprintf(format, args...),
+			Placeholder: placeholder,
+			Location:    loc,
+		})
+	}
+
+	d := t.delimiters()
+	if t.matchBrokenPlaceholder(contents, true) {
+		add(SeverityError, contents, "unbalanced braces: %q without %q", d.open, d.close)
+	}
+	if t.matchBrokenPlaceholder(contents, false) {
+		add(SeverityError, contents, "unbalanced braces: %q without %q", d.close, d.open)
+	}
+
+	for _, s := range d.reLike.FindAllString(contents, -1) {
+		if d.reParam.FindString(s) != s && !t.isBlockMarker(s) {
+			add(SeverityWarning, s, "not a valid placeholder, left as text")
+		}
+	}
+
+	// row placeholders of the same row must come from the same slice
+	// {{Friends.Name}} {{Pets.Name}} -- rows can't be multiplied by both.
+	// Only loop keys are known to be of slice, other dotted keys may be
+	// struct fields {{Address.City}} {{Friends.Name}} -- hint for those
+	var names, rowNames []string
+	for _, info := range t.inspectContent([]byte(contents)) {
+		if info.Expand != ExpandRow && info.Expand != ExpandUnknown {
+			continue
+		}
+		name := strings.SplitN(info.Key, ".", 2)[0]
+		if info.Expand == ExpandRow && !inSlice(name, rowNames) {
+			rowNames = append(rowNames, name)
+		}
+		if !inSlice(name, names) {
+			names = append(names, name)
+		}
+	}
+	switch {
+	case len(rowNames) > 1:
+		add(SeverityWarning, contents, "row mixes keys of different slices: %s", strings.Join(rowNames, ", "))
+	case len(names) > 1:
+		add(SeverityInfo, contents, "row mixes keys of %s: only one of them can be a slice", strings.Join(names, ", "))
+	}
+
+	// block marker works only as the only text of paragraph
+	paragraphs := []*xmlNode{nrow}
+	if nrow.Tag() != "w-p" {
+		paragraphs = nil
+		nrow.Walk(func(n *xmlNode) {
+			if n.Tag() == "w-p" {
+				paragraphs = append(paragraphs, n)
+			}
+		})
+	}
+	for _, np := range paragraphs {
+		if t.blockMarkerOf(np) != nil {
+			continue
+		}
+		for _, s := range d.reLike.FindAllString(string(np.AllContents()), -1) {
+			if t.isBlockMarker(s) {
+				add(SeverityWarning, s, "block marker must be the only text of paragraph, left as text")
+			}
+		}
+	}
+
+	return list
+}
+
+// lintBlocks - block markers not paired inside of the same parent
+func (t *Template) lintBlocks(part string, xnode *xmlNode) []Diagnostic {
+	var list []Diagnostic
+	check := func(n *xmlNode) {
+		_, err := t.blocksOf(n)
+		if err, ok := err.(*BlockError); ok {
+			list = append(list, Diagnostic{
+				Severity:    SeverityError,
+				Message:     err.Err.Error(),
+				Placeholder: err.Marker,
+				Location:    Location{Part: part, Kind: LocationParagraph, Text: err.Marker},
+			})
+		}
+	}
+
+	check(xnode)
+	xnode.Walk(func(n *xmlNode) {
+		if n.childFirst != nil {
+			check(n)
+		}
+	})
+	return list
+}
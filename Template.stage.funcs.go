@@ -3,13 +3,15 @@ package docxplate
 import (
 	"bytes"
 	"encoding/xml"
+	"sort"
 	"strings"
 	"sync"
 )
 
-// Collect and trigger placeholders with trigger but unset in `t.params`
-// Placeholders with trigger `:empty` must be triggered
-// otherwise they are left
+// Collect and trigger placeholders with trigger but unset in `t.params`.
+// Their key is unknown, or null or empty when it's in null or empty
+// slice/struct, so `:unknown`, `:null` or `:empty` trigger is run.
+// Placeholder of whole slice/struct has no text, so it's empty
 func (t *Template) triggerMissingParams(xnode *xmlNode) {
 	if t.params == nil {
 		return
@@ -18,28 +20,96 @@ func (t *Template) triggerMissingParams(xnode *xmlNode) {
 	var triggerParams ParamList
 
 	xnode.Walk(func(n *xmlNode) {
-		if !n.isRowElement() || !n.HaveParams() {
+		if !n.isRowElement() || !n.HaveParams(t.delimiters()) {
 			return
 		}
-		p := NewParamFromRaw(n.AllContents())
-		if p != nil && p.Trigger != nil {
-			triggerParams = append(triggerParams, p)
+		p := newParamFromRaw(n.AllContents(), t.delimiters())
+		if p == nil || p.Trigger == nil {
+			return
+		}
+
+		switch t.params.keyState(p.AbsoluteKey) {
+		case ParamStateUnknown:
+			p.unknown = true
+		case ParamStateNull:
+			p.null = true
+		case ParamStateValue:
+			if kp := t.params.findByKey(p.AbsoluteKey); kp == nil || kp.Type == StringParam {
+				return
+			}
 		}
+		triggerParams = append(triggerParams, p)
 	})
 
 	if triggerParams == nil {
 		return
 	}
 
-	// make sure not to "tint" original t.params
-	_params := t.params
-	t.params = triggerParams
+	// do stuff only with filtered params,
+	// all params are still there for :if(Key) to look up
+	t.replaceSingleParams(xnode, triggerParams, t.params, true)
+}
 
-	// do stuff only with filtered params
-	t.replaceSingleParams(xnode, true)
+// Collect errors of invalid triggers in placeholders
+func (t *Template) checkTriggers(xnode *xmlNode) {
+	xnode.Walk(func(n *xmlNode) {
+		if len(n.Content) == 0 {
+			return
+		}
+		for _, match := range t.delimiters().reParam.FindAllSubmatch(n.Content, -1) {
+			if _, err := placeholderTriggers(string(match[1]) == DirectivePrefix, match[4]); err != nil {
+				t.errs = append(t.errs, &TriggerError{
+					Part:    t.part,
+					Key:     strings.TrimSpace(string(match[2])),
+					Trigger: strings.TrimSpace(string(match[4])),
+					Err:     err,
+				})
+			}
+		}
+	})
+}
+
+// Handle placeholders left without value by missing key policy:
+// keep them, blank them or collect them as unresolved
+func (t *Template) handleMissingKeys(xnode *xmlNode) {
+	if t.missingKey == MissingKeyKeep {
+		return
+	}
+
+	// blank or collect placeholders found in buf
+	handle := func(n *xmlNode, buf []byte, isAttr bool) []byte {
+		matches := t.delimiters().reParam.FindAllSubmatch(buf, -1)
+		if matches == nil {
+			return buf
+		}
+
+		if t.missingKey == MissingKeyBlank {
+			return t.delimiters().reParam.ReplaceAll(buf, nil)
+		}
 
-	// back to original
-	t.params = _params
+		loc := t.locate(t.part, n)
+		if isAttr {
+			loc.Kind = LocationAttribute
+			loc.Text = string(buf)
+		}
+		for _, match := range matches {
+			t.unresolved = append(t.unresolved, UnresolvedPlaceholder{
+				Placeholder: string(match[0]),
+				Key:         strings.TrimSpace(string(match[2])),
+				Location:    loc,
+			})
+		}
+		return buf
+	}
+
+	xnode.Walk(func(n *xmlNode) {
+		for i := range n.Attrs {
+			n.Attrs[i].Value = string(handle(n, []byte(n.Attrs[i].Value), true))
+		}
+		if len(n.Content) > 0 {
+			n.Content = handle(n, n.Content, false)
+		}
+	})
 }
 
 // Expand complex placeholders
@@ -57,7 +127,15 @@ func (t *Template) expandPlaceholders(xnode *xmlNode) {
 		}
 		var max int
 		contents := nrow.AllContents()
-		rowParams := rowParams(contents)
+		rowParams := rowParams(contents, t.delimiters())
+
+		// row with slice modifiers is expanded to items of its view
+		data := t.params
+		view := t.sliceView(rowParams)
+		if view != nil {
+			data = view.params
+		}
+		var deleted bool
 		rowPlaceholders := make(map[string]*placeholder)
 		for _, rowParam := range rowParams {
 			placeholderType := rowPlaceholder
@@ -66,39 +144,79 @@ func (t *Template) expandPlaceholders(xnode *xmlNode) {
 			}
 
 			params := rowParam.paramsSuffix()
+			var prefix string
+			if rowParam.Directive {
+				prefix = DirectivePrefix
+			}
 
-			paramData := t.params.FindAllByKey(rowParam.AbsoluteKey)
+			paramData := data.FindAllByKey(rowParam.AbsoluteKey)
 			if len(paramData) == 0 {
+				if view != nil && len(splitModifiers(rowParam.modifiers).slice) > 0 && len(t.params.slicesOf(rowParam.AbsoluteKey)) > 0 {
+					// slice modifiers left no items
+					defer once.Do(nrow.delete)
+					deleted = true
+				}
 				continue
 			}
 			placeholders := make([]string, paramData[len(paramData)-1].Index)
 
 			for _, param := range paramData {
-				placeholders[param.Index-1] = "{{" + param.AbsoluteKey + params + "}}"
+				placeholders[param.Index-1] = t.delimiters().wrap(prefix + param.AbsoluteKey + params)
 			}
 			rowPlaceholders[rowParam.RowPlaceholder] = &placeholder{
 				Type:         placeholderType,
 				Placeholders: placeholders,
 				Separator:    strings.TrimLeft(rowParam.Separator, " "),
+				prefix:       prefix,
+				params:       params,
+				data:         paramData,
 			}
-			if max < len(placeholders) {
+			if placeholderType == rowPlaceholder && max < len(placeholders) {
 				max = len(placeholders)
 			}
 		}
+
+		// Keys of parent slices in the row of nested slice
+		// {{Friends.Name}} {{Friends.Friends.Name}} -- parent value
+		// is repeated in every row of its nested slice
+		rows := t.fillParentPlaceholders(rowPlaceholders, view)
+		if len(rows) > 0 {
+			max = len(rows)
+		} else {
+			t.fillItemPlaceholders(rowPlaceholders, max)
+		}
+
+		// Inline placeholders first, so row clones get them expanded too.
+		// Those of slices nested in row items are expanded in every clone
+		deepest := deepestRowData(rowPlaceholders)
+		if rows == nil {
+			rows = make([]*Param, max)
+			for _, p := range deepest {
+				if p.Index <= max {
+					rows[p.Index-1] = p
+				}
+			}
+		}
+		for oldPlaceholder, newPlaceholder := range rowPlaceholders {
+			if newPlaceholder.Type != inlinePlaceholder || len(deepest) > 0 && itemScope(deepest[0], newPlaceholder.data) != "" {
+				continue
+			}
+			nrow.Walk(func(n *xmlNode) {
+				if !inSlice(n.XMLName.Local, []string{"w-t"}) || len(n.Content) == 0 {
+					return
+				}
+				n.Content = bytes.ReplaceAll(n.Content, []byte(oldPlaceholder), []byte(strings.Join(newPlaceholder.Placeholders, newPlaceholder.Separator)))
+			})
+		}
+
 		nnews := make([]*xmlNode, max)
 		for oldPlaceholder, newPlaceholder := range rowPlaceholders {
 			switch newPlaceholder.Type {
-			case inlinePlaceholder:
-				nrow.Walk(func(n *xmlNode) {
-					if !inSlice(n.XMLName.Local, []string{"w-t"}) || len(n.Content) == 0 {
-						return
-					}
-					n.Content = bytes.ReplaceAll(n.Content, []byte(oldPlaceholder), []byte(strings.Join(newPlaceholder.Placeholders, newPlaceholder.Separator)))
-				})
 			case rowPlaceholder:
 				defer once.Do(func() {
 					nrow.delete()
 				})
+				deleted = true
 				for i := max - 1; i >= 0; i-- {
 					if nnews[i] == nil {
 						nnews[i] = nrow.cloneAndAppend()
@@ -119,6 +237,36 @@ func (t *Template) expandPlaceholders(xnode *xmlNode) {
 			}
 		}
 
+		// Inline placeholders of slices nested in row items: {{Cs.Name}} {{Cs.Items.T , }}
+		// joins items of its own row item only
+		for oldPlaceholder, newPlaceholder := range rowPlaceholders {
+			if newPlaceholder.Type != inlinePlaceholder {
+				continue
+			}
+			for i, rowParam := range rows {
+				if rowParam == nil || nnews[i] == nil {
+					continue
+				}
+				scope := itemScope(rowParam, newPlaceholder.data)
+				if scope == "" {
+					continue
+				}
+
+				var placeholders []string
+				for _, p := range newPlaceholder.data {
+					if strings.HasPrefix(p.AbsoluteKey, scope+".") {
+						placeholders = append(placeholders, t.delimiters().wrap(newPlaceholder.prefix+p.AbsoluteKey+newPlaceholder.params))
+					}
+				}
+				nnews[i].Walk(func(n *xmlNode) {
+					if !inSlice(n.XMLName.Local, []string{"w-t"}) || len(n.Content) == 0 {
+						return
+					}
+					n.Content = bytes.ReplaceAll(n.Content, []byte(oldPlaceholder), []byte(strings.Join(placeholders, newPlaceholder.Separator)))
+				})
+			}
+		}
+
 		// Vertical merge: mark cloned rows cells holding `:vmerge` placeholders.
 		// First cloned row gets vMerge "restart", all the next rows - "continue"
 		if bytes.Contains(contents, []byte(ParamVMerge)) {
@@ -129,20 +277,230 @@ func (t *Template) expandPlaceholders(xnode *xmlNode) {
 				applyVMerge(nn, i)
 			}
 		}
+
+		// Rows of view are rendered with its params right away,
+		// so loop keys and :if(Key) are those of items as shown
+		if view != nil {
+			rows := nnews
+			if !deleted {
+				rows = append(rows, nrow)
+			}
+			for _, nn := range rows {
+				if nn == nil {
+					continue
+				}
+				t.runDirectives(nn, view.params)
+				t.replaceSingleParams(nn, view.params, view.params, false)
+			}
+		}
 		return true
 	})
 }
 
-// Replace single params by type
-func (t *Template) replaceSingleParams(xnode *xmlNode, triggerParamOnly bool) {
+// fillParentPlaceholders - row placeholders of parent slices get placeholder
+// of their item in every row of the deepest slice in the same row.
+// Parent item without nested items gets a row of its own, where keys
+// of nested slices are those of the item: {{A.B.Name}} --> {{A.2.B.Name}},
+// left for triggers and missing key policy. Slice items the row is
+// expanded to are returned, nil if there are no nested slices in row
+func (t *Template) fillParentPlaceholders(rowPlaceholders map[string]*placeholder, view *sliceView) []*Param {
+	deepest := deepestRowData(rowPlaceholders)
+	if deepest == nil {
+		return nil
+	}
+
+	// slice items of every level found in row data
+	nested := false
+	children := map[*Param][]*Param{}
+	seen := map[*Param]bool{}
+	for _, ph := range rowPlaceholders {
+		if ph.Type != rowPlaceholder {
+			continue
+		}
+		nested = nested || sliceLevels(ph.data[0]) < sliceLevels(deepest[0])
+		for _, p := range ph.data {
+			for item := sliceItemOf(p); item != nil && !seen[item]; item = sliceItemOf(item.parent) {
+				seen[item] = true
+				parent := sliceItemOf(item.parent)
+				children[parent] = append(children[parent], item)
+			}
+		}
+	}
+	if !nested {
+		return nil
+	}
+
+	// rows in data order: items of the deepest slice,
+	// and parent items without nested items
+	var rows []*Param
+	var walk func(parent *Param)
+	walk = func(parent *Param) {
+		items := children[parent]
+		// view is nil for rows without slice modifiers,
+		// its itemIndex falls back to data order then
+		sort.SliceStable(items, func(i, j int) bool {
+			return view.itemIndex(items[i]) < view.itemIndex(items[j])
+		})
+		for _, item := range items {
+			if len(children[item]) == 0 {
+				rows = append(rows, item)
+				continue
+			}
+			walk(item)
+		}
+	}
+	walk(nil)
+
+	for _, ph := range rowPlaceholders {
+		if ph.Type != rowPlaceholder {
+			continue
+		}
+		levels := sliceLevels(ph.data[0])
+
+		items := make(map[string]*Param, len(ph.data))
+		for _, p := range ph.data {
+			items[sliceItemKey(p)] = p
+		}
+
+		placeholders := make([]string, len(rows))
+		for i, row := range rows {
+			item := row
+			for item != nil && sliceLevels(item) > levels {
+				item = sliceItemOf(item.parent)
+			}
+			switch {
+			case item == nil:
+			case sliceLevels(item) < levels:
+				// nested slice of row item is empty or missing
+				key := item.AbsoluteKey + strings.TrimPrefix(ph.data[0].CompactKey, item.CompactKey)
+				placeholders[i] = t.delimiters().wrap(ph.prefix + key + ph.params)
+			case items[item.AbsoluteKey] != nil:
+				placeholders[i] = t.delimiters().wrap(ph.prefix + items[item.AbsoluteKey].AbsoluteKey + ph.params)
+			}
+		}
+		ph.Placeholders = placeholders
+	}
+	return rows
+}
+
+// fillItemPlaceholders - row placeholders get key of row item where item
+// has no such param: {{F.Phone}} --> {{F.2.Phone}}, so triggers and
+// missing key policy see the state of that item, not of the compact key
+// found in other items
+func (t *Template) fillItemPlaceholders(rowPlaceholders map[string]*placeholder, max int) {
+	items := make([]*Param, max)
+	for _, ph := range rowPlaceholders {
+		if ph.Type != rowPlaceholder {
+			continue
+		}
+		for _, p := range ph.data {
+			if item := sliceItemOf(p); item != nil && p.Index <= max {
+				items[p.Index-1] = item
+			}
+		}
+	}
+
+	for _, ph := range rowPlaceholders {
+		if ph.Type != rowPlaceholder {
+			continue
+		}
+		first := sliceItemOf(ph.data[0])
+		if first == nil {
+			continue
+		}
+		key := strings.TrimPrefix(ph.data[0].CompactKey, first.CompactKey)
+
+		for len(ph.Placeholders) < max {
+			ph.Placeholders = append(ph.Placeholders, "")
+		}
+		for i, item := range items {
+			if item != nil && ph.Placeholders[i] == "" {
+				ph.Placeholders[i] = t.delimiters().wrap(ph.prefix + item.AbsoluteKey + key + ph.params)
+			}
+		}
+	}
+}
+
+// deepestRowData - data of row placeholder of the deepest slice,
+// its items are rows the row is expanded to
+func deepestRowData(rowPlaceholders map[string]*placeholder) ParamList {
+	var deepest ParamList
+	for _, ph := range rowPlaceholders {
+		if ph.Type != rowPlaceholder {
+			continue
+		}
+		if deepest == nil || sliceLevels(ph.data[0]) > sliceLevels(deepest[0]) {
+			deepest = ph.data
+		}
+	}
+	return deepest
+}
+
+// itemScope - absolute key of row param's slice item holding slice
+// of data nested in it, empty if data is not nested in row items:
+// Cs.1.Name and Cs.*.Items.*.T --> Cs.1
+func itemScope(rowParam *Param, data ParamList) string {
+	if len(data) == 0 {
+		return ""
+	}
+	inner := sliceItemOf(data[0])
+	if inner == nil {
+		return ""
+	}
+	for item := sliceItemOf(rowParam); item != nil; item = sliceItemOf(item.parent) {
+		if strings.HasPrefix(inner.CompactKey, item.CompactKey+".") {
+			return item.AbsoluteKey
+		}
+	}
+	return ""
+}
+
+// sliceItemOf - innermost slice item param is (or is in), nil if none
+func sliceItemOf(p *Param) *Param {
+	for ; p != nil && p.parent != nil; p = p.parent {
+		if p.parent.Type == SliceParam {
+			return p
+		}
+	}
+	return nil
+}
+
+// sliceLevels - how many slices param is nested in
+// {{Friends.2.Friends.1.Name}} --> 2
+func sliceLevels(p *Param) int {
+	var levels int
+	for ; p.parent != nil; p = p.parent {
+		if p.parent.Type == SliceParam {
+			levels++
+		}
+	}
+	return levels
+}
+
+// sliceItemKey - absolute key of slice item param belongs to
+// {{Friends.2.Name}} --> Friends.2
+func sliceItemKey(p *Param) string {
+	for ; p.parent != nil; p = p.parent {
+		if p.parent.Type == SliceParam {
+			return p.AbsoluteKey
+		}
+	}
+	return ""
+}
+
+// Replace single params by type. Triggers look :if(Key) up in lookup params.
+// Keys of params are set already, walk them without changing them
+func (t *Template) replaceSingleParams(xnode *xmlNode, params, lookup ParamList, triggerParamOnly bool) {
 	paramAbsoluteKeyMap := map[string]*Param{}
+	env := t.triggerEnv(lookup)
 
-	t.params.Walk(func(p *Param) {
+	params.WalkWithEnd(func(p *Param) bool {
 		if p.Type != StringParam && p.Type != ImageParam {
-			return
+			return false
 		}
 		// log.Printf("[%s][%s]", p.AbsoluteKey, p.Value)
 		paramAbsoluteKeyMap[p.AbsoluteKey] = p
+		return false
 	})
 
 	xnode.Walk(func(n *xmlNode) {
@@ -157,12 +515,12 @@ func (t *Template) replaceSingleParams(xnode *xmlNode, triggerParamOnly bool) {
 			}
 		}
 
-		for _, key := range n.GetContentPrefixList() {
+		for _, key := range n.GetContentPrefixList(t.delimiters()) {
 			p, ok := paramAbsoluteKeyMap[key]
 			if !ok {
 				continue
 			}
-			t.replaceAndRunTrigger(p, n, triggerParamOnly)
+			t.replaceAndRunTrigger(p, n, env, triggerParamOnly)
 		}
 
 		// for _, p := range t.params {
@@ -176,13 +534,21 @@ func (t *Template) replaceSingleParams(xnode *xmlNode, triggerParamOnly bool) {
 	})
 }
 
-func (t *Template) replaceAndRunTrigger(p *Param, n *xmlNode, triggerParamOnly bool) {
+// triggerEnv - params and settings of template triggers run with
+func (t *Template) triggerEnv(params ParamList) triggerEnv {
+	return triggerEnv{params: params, strictEmpty: t.strictEmpty}
+}
+
+func (t *Template) replaceAndRunTrigger(p *Param, n *xmlNode, env triggerEnv, triggerParamOnly bool) {
 	// log.Printf("replaceAndRunTrigger: %v", p.AbsoluteKey)
 
+	// Param is reused for every node, take params part of this one
+	p.extractModifiers(n.Content)
+
 	// Trigger: does placeholder have trigger
 	if p.Trigger = p.extractTriggerFrom(n.Content); p.Trigger != nil {
 		defer func() {
-			p.RunTrigger(n)
+			p.runTriggers(n, env)
 		}()
 	}
 
@@ -195,12 +561,30 @@ func (t *Template) replaceAndRunTrigger(p *Param, n *xmlNode, triggerParamOnly b
 	case StringParam:
 		// log.Printf("-- StringParam: %v", p.AbsoluteKey)
 		p.VMerge = p.extractVMerge(n.Content)
-		if p.Formatter = p.extractFormatter(n.Content); p.Formatter != nil {
-			result := p.Formatter.ApplyFormat(p.Formatter.Format, []byte(p.Value))
-			p.Value = string(result)
+		p.Formatter = p.extractFormatter(n.Content)
+
+		// Empty value gets default text of placeholder as is, not formatted.
+		// It's template text, escaped already
+		if text, ok := p.extractDefault(n.Content); ok && p.Value == "" {
+			t.replaceTextParamWith(n, p, string(unescapeChars([]byte(text))))
+			return
 		}
-		t.replaceTextParam(n, p)
+
+		if p.Formatter == nil {
+			t.replaceTextParam(n, p)
+			return
+		}
+
+		formatted, err := p.Formatter.format(p, formatEnv{locale: t.locale, formatters: t.formatters})
+		if err != nil {
+			t.errs = append(t.errs, &FormatError{Part: t.part, Key: p.AbsoluteKey, Format: p.Formatter.String(), Err: err})
+		}
+		t.replaceTextParamWith(n, p, formatted)
 	case ImageParam:
+		if err := t.prepareImageParam(p); err != nil {
+			t.errs = append(t.errs, &ImageError{Part: t.part, Key: p.AbsoluteKey, Image: p.image, Err: err})
+			return
+		}
 		t.replaceImageParams(n, p)
 	}
 }
@@ -295,21 +679,39 @@ func (t *Template) fixBrokenPlaceholders(xnode *xmlNode) {
 			}
 
 			if brokenNode != nil {
-				// fmt.Printf("OK [%s] + [%s]\n", aurora.Green(brokenNode.AllContents()), aurora.Green(n.AllContents()))
-				brokenNode.Content = append(brokenNode.Content, n.AllContents()...)
-				// aurora.Magenta("[%s] %v -- %v -- %v -- %v", brokenNode.Content, brokenNode.Tag(), brokenNode.parent.Tag(), brokenNode.parent.parent.Tag(), brokenNode.parent.parent.parent.Tag())
-				n.delete()
-				return
+				content, next := string(brokenNode.Content), string(n.AllContents())
+				// merge only while placeholder is not complete, runs after it keep their styles
+				if t.matchBrokenLeftPlaceholder(content) || t.matchSplitOpenDelimiter(content, next) {
+					// fmt.Printf("OK [%s] + [%s]\n", aurora.Green(brokenNode.AllContents()), aurora.Green(n.AllContents()))
+					brokenNode.Content = append(brokenNode.Content, n.AllContents()...)
+					// aurora.Magenta("[%s] %v -- %v -- %v -- %v", brokenNode.Content, brokenNode.Tag(), brokenNode.parent.Tag(), brokenNode.parent.parent.Tag(), brokenNode.parent.parent.parent.Tag())
+					n.delete()
+					return
+				}
+				if next == "" {
+					return // no text yet to tell if delimiter split goes on
+				}
 			}
 
-			if t.matchBrokenLeftPlaceholder(string(n.Content)) {
+			// every text node may end with open delimiter split, next node tells
+			brokenNode = nil
+			if len(n.Content) > 0 {
 				// nrow.printTree("BROKEN")
 				brokenNode = n
-				return
 			}
 
-			brokenNode = nil
-
 		})
 	})
 }
+
+// escapeDelimiters - hide escaped delimiters `\{{Key}}` from placeholder search,
+// they are turned to plain `{{Key}}` when document is saved
+func (t *Template) escapeDelimiters(xnode *xmlNode) {
+	d := t.delimiters()
+	xnode.Walk(func(n *xmlNode) {
+		n.Content = d.escape(n.Content)
+		for i := range n.Attrs {
+			n.Attrs[i].Value = string(d.escape([]byte(n.Attrs[i].Value)))
+		}
+	})
+}
@@ -0,0 +1,64 @@
+// docxplate-lint - check docx templates for broken or invalid placeholders
+//
+//	docxplate-lint [-strict] [-open "[[" -close "]]"] template.docx [template2.docx ...]
+//
+// Exits with status 1 when any template has errors, 2 on usage or read errors
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/bobiverse/docxplate"
+)
+
+func main() {
+	strict := flag.Bool("strict", false, "treat warnings as errors")
+	openDelim := flag.String("open", "", "opening delimiter of placeholders (default \"{{\")")
+	closeDelim := flag.String("close", "", "closing delimiter of placeholders (default \"}}\")")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-strict] [-open delim -close delim] template.docx [template2.docx ...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var opts []docxplate.Option
+	if *openDelim != "" || *closeDelim != "" {
+		opts = append(opts, docxplate.WithDelimiters(*openDelim, *closeDelim))
+	}
+
+	failAt := docxplate.SeverityError
+	if *strict {
+		failAt = docxplate.SeverityWarning
+	}
+
+	status := 0
+	for _, fpath := range flag.Args() {
+		tdoc, err := docxplate.OpenTemplate(fpath, opts...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", fpath, err)
+			os.Exit(2)
+		}
+
+		list, err := tdoc.Lint()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", fpath, err)
+			os.Exit(2)
+		}
+
+		for _, d := range list {
+			fmt.Printf("%s: %s\n", fpath, d)
+			if d.Severity >= failAt {
+				status = 1
+			}
+		}
+	}
+
+	os.Exit(status)
+}
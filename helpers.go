@@ -3,30 +3,38 @@ package docxplate
 import (
 	"bytes" // #nosec  G501 - allowed weak hash
 	"encoding/xml"
+	"errors"
+	"fmt"
 	"io"
 	"log"
 )
 
 func readerBytes(rdr io.ReadCloser) []byte {
+	buf, err := readAllAndClose(rdr)
+	if err != nil {
+		log.Printf("%s", err)
+		return nil
+	}
+	return buf
+}
+
+// read all bytes from reader and close it
+func readAllAndClose(rdr io.ReadCloser) ([]byte, error) {
 	buf := new(bytes.Buffer)
 
 	if rdr == nil {
-		log.Printf("can't read bytes from empty reader")
-		return nil
-
+		return nil, errors.New("can't read bytes from empty reader")
 	}
 
 	if _, err := buf.ReadFrom(rdr); err != nil {
-		log.Printf("can't read bytes: %s", err)
-		return nil
+		return nil, fmt.Errorf("can't read bytes: %w", err)
 	}
 
 	if err := rdr.Close(); err != nil {
-		log.Printf("can't close reader: %s", err)
-		return nil
+		return nil, fmt.Errorf("can't close reader: %w", err)
 	}
 
-	return buf.Bytes()
+	return buf.Bytes(), nil
 }
 
 // xmlDeclaration - standard XML declaration for OOXML parts
@@ -12,4 +12,8 @@ type placeholder struct {
 	Type         placeholderType
 	Placeholders []string
 	Separator    string
+
+	prefix string    // directive mark of row placeholder key
+	params string    // formatter, trigger, vmerge suffix of row placeholder
+	data   ParamList // params found for placeholder key
 }
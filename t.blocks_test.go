@@ -0,0 +1,401 @@
+package docxplate_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/bobiverse/docxplate"
+)
+
+func TestBlocks(t *testing.T) {
+	assertFixtureLines(t, "blocks.docx",
+		"{{#if Insurance}}",
+		"Insurance: insured by {{Insurance.Company}}",
+		"Insurance: table of {{Insurance.Company}}",
+		"{{else}}",
+		"Insurance: not insured",
+		"{{/if}}",
+	)
+
+	var tt = []struct {
+		params   map[string]any
+		expected []string
+	}{
+		{
+			map[string]any{"Insurance": map[string]any{"Company": "ACME"}},
+			[]string{"Insurance: start", "Insurance: insured by ACME", "Insurance: table of ACME", "Insurance: end"},
+		},
+		{
+			map[string]any{"Insurance": nil},
+			[]string{"Insurance: start", "Insurance: not insured", "Insurance: end"},
+		},
+		{
+			map[string]any{},
+			[]string{"Insurance: start", "Insurance: not insured", "Insurance: end"},
+		},
+		{
+			// slice is true when not empty, whatever its first item is
+			map[string]any{"Insurance": []string{"", "555"}},
+			[]string{"Insurance: start", "Insurance: insured by {{Insurance.Company}}", "Insurance: table of {{Insurance.Company}}", "Insurance: end"},
+		},
+		{
+			map[string]any{"Insurance": []string{"false", "x"}},
+			[]string{"Insurance: start", "Insurance: insured by {{Insurance.Company}}", "Insurance: table of {{Insurance.Company}}", "Insurance: end"},
+		},
+		{
+			map[string]any{"Insurance": []string{}},
+			[]string{"Insurance: start", "Insurance: not insured", "Insurance: end"},
+		},
+	}
+
+	for _, tc := range tt {
+		tdoc := renderFixture(t, "blocks.docx", tc.params)
+
+		lines := renderedLines(tdoc.Plaintext(), "Insurance: ")
+		if strings.Join(lines, "|") != strings.Join(tc.expected, "|") {
+			t.Fatalf("%v: expected %q, got: %q", tc.params, tc.expected, lines)
+		}
+		if s := tdoc.Plaintext(); strings.Contains(s, "{{#if") || strings.Contains(s, "{{else}}") || strings.Contains(s, "{{/if}}") {
+			t.Fatalf("%v: markers must be removed: %s", tc.params, s)
+		}
+	}
+}
+
+func TestBlocksNested(t *testing.T) {
+	assertFixtureLines(t, "blocks.docx", "{{#if A}}", "Nested: A", "{{#if B}}", "Nested: A and B", "Nested: A not B")
+
+	var tt = []struct {
+		params   map[string]any
+		expected []string
+	}{
+		{map[string]any{"A": true, "B": true}, []string{"Nested: A", "Nested: A and B", "Nested: end"}},
+		{map[string]any{"A": true, "B": 0}, []string{"Nested: A", "Nested: A not B", "Nested: end"}},
+		{map[string]any{"A": false, "B": true}, []string{"Nested: end"}},
+	}
+
+	for _, tc := range tt {
+		tdoc := renderFixture(t, "blocks.docx", tc.params)
+
+		lines := renderedLines(tdoc.Plaintext(), "Nested: ")
+		if strings.Join(lines, "|") != strings.Join(tc.expected, "|") {
+			t.Fatalf("%v: expected %q, got: %q", tc.params, tc.expected, lines)
+		}
+	}
+}
+
+// TestBlocksIfUnknown - unknown key of #if is false, unresolved with MissingKeyFail
+func TestBlocksIfUnknown(t *testing.T) {
+	params := map[string]any{"A": false, "Annex": false, "Insured": false, "Contracts": []any{}, "Tags": []any{}}
+
+	tdoc, _ := docxplate.OpenTemplate("test-data/blocks.docx")
+	if err := tdoc.Render(params); err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+	if lines := renderedLines(tdoc.Plaintext(), "Insurance: "); strings.Join(lines, "|") != "Insurance: start|Insurance: not insured|Insurance: end" {
+		t.Fatalf("expected else part, got: %q", lines)
+	}
+
+	tdoc, _ = docxplate.OpenTemplate("test-data/blocks.docx")
+	err := tdoc.Render(params, docxplate.WithMissingKey(docxplate.MissingKeyFail))
+	if markers := unresolvedMarkers(err); strings.Join(markers, " ") != "{{#if Insurance}}" {
+		t.Fatalf("expected {{#if Insurance}} unresolved, got: %v", err)
+	}
+	if lines := renderedLines(tdoc.Plaintext(), "Insurance: "); strings.Join(lines, "|") != "Insurance: start|Insurance: not insured|Insurance: end" {
+		t.Fatalf("expected else part, got: %q", lines)
+	}
+}
+
+// unresolvedMarkers - block markers of *UnresolvedError in err
+func unresolvedMarkers(err error) []string {
+	var unresolvedErr *docxplate.UnresolvedError
+	if !errors.As(err, &unresolvedErr) {
+		return nil
+	}
+
+	var markers []string
+	for _, up := range unresolvedErr.Placeholders {
+		if strings.HasPrefix(up.Placeholder, "{{#") {
+			markers = append(markers, up.Placeholder)
+		}
+	}
+	return markers
+}
+
+// TestBlocksSectionBreaks - breaks inside of block go with it,
+// break of marker stays when block is kept
+func TestBlocksSectionBreaks(t *testing.T) {
+	var tt = []struct {
+		annex  bool
+		breaks int
+	}{
+		{true, 2},
+		{false, 0},
+	}
+
+	for _, tc := range tt {
+		tdoc, _ := docxplate.OpenTemplate("test-data/blocks.docx")
+		buf, _ := tdoc.Bytes()
+		before := strings.Count(documentXMLFromBytes(t, buf), `<w:type w:val="nextPage"`)
+		if err := tdoc.Render(map[string]any{"Annex": tc.annex}); err != nil {
+			t.Fatalf("Render: %s", err)
+		}
+
+		buf, err := tdoc.Bytes()
+		if err != nil {
+			t.Fatalf("Bytes: %s", err)
+		}
+		xml := documentXMLFromBytes(t, buf)
+		if breaks := strings.Count(xml, `<w:type w:val="nextPage"`) - (before - 2); breaks != tc.breaks {
+			t.Fatalf("annex=%v: expected %d section breaks, got: %d", tc.annex, tc.breaks, breaks)
+		}
+		if strings.Contains(xml, "{{/if}}") {
+			t.Fatalf("annex=%v: markers must be removed", tc.annex)
+		}
+	}
+}
+
+// TestBlocksInCell - cell left without paragraphs by block gets an empty one
+func TestBlocksInCell(t *testing.T) {
+	assertFixtureLines(t, "blocks.docx", "{{#if Insured}}", "Cell: insured", "Cell: {{Name}}")
+
+	for _, params := range []map[string]any{{"Name": "Alice"}, {"Name": "Alice", "Insured": true}} {
+		tdoc := renderFixture(t, "blocks.docx", params)
+
+		buf, err := tdoc.Bytes()
+		if err != nil {
+			t.Fatalf("Bytes: %s", err)
+		}
+		doc := documentXMLFromBytes(t, buf)
+		if regexp.MustCompile(`<w:tc>(<w:tcPr>.*?</w:tcPr>)?</w:tc>`).MatchString(doc) {
+			t.Fatalf("%v: cell without paragraph: %s", params, doc)
+		}
+
+		_, insured := params["Insured"]
+		if kept := len(renderedLines(tdoc.Plaintext(), "Cell: insured")) > 0; kept != insured {
+			t.Fatalf("%v: expected block kept=%v: %s", params, insured, tdoc.Plaintext())
+		}
+	}
+}
+
+// TestBlocksInvalid - test-data/blocks.invalid.docx holds every case
+// in table cell of its own, markers are paired inside of the same parent
+func TestBlocksInvalid(t *testing.T) {
+	var tt = []struct {
+		cell   []string // paragraphs of fixture cell
+		marker string
+	}{
+		{[]string{"{{#if A}}", "Clause: A"}, "{{#if A}}"},
+		{[]string{"Clause: A", "{{/if}}"}, "{{/if}}"},
+		{[]string{"{{else}}", "{{/if}}"}, "{{else}}"},
+		{[]string{"{{#if A}}", "{{else}}", "{{else}}", "{{/if}}"}, "{{else}}"},
+		{[]string{"{{#if}}", "{{/if}}"}, "{{#if}}"},
+		{[]string{"{{#if A}}"}, "{{#if A}}"}, // and table with {{/if}}
+		{[]string{"{{/if}}"}, "{{/if}}"},     // of that table
+		{[]string{"{{#each A}}", "{{/if}}"}, "{{/if}}"},
+		{[]string{"{{#if A}}", "{{/each}}"}, "{{/each}}"},
+		{[]string{"{{#each A}}", "{{else}}", "{{/each}}"}, "{{else}}"},
+		{[]string{"{{#each A}}"}, "{{#each A}}"},
+	}
+	var expected []string
+	for _, tc := range tt {
+		assertFixtureLines(t, "blocks.invalid.docx", tc.cell...)
+		expected = append(expected, tc.marker)
+	}
+	assertFixtureLines(t, "blocks.invalid.docx", "Hi {{#if A}}")
+
+	tdoc, _ := docxplate.OpenTemplate("test-data/blocks.invalid.docx")
+	err := tdoc.Render(map[string]any{"A": []string{"a"}})
+
+	var markers []string
+	for _, err := range joinedErrors(err) {
+		var blockErr *docxplate.BlockError
+		if errors.As(err, &blockErr) {
+			markers = append(markers, blockErr.Marker)
+		}
+	}
+	if strings.Join(markers, " ") != strings.Join(expected, " ") {
+		t.Fatalf("expected *BlockError of %q, got: %v", expected, err)
+	}
+
+	tdoc, _ = docxplate.OpenTemplate("test-data/blocks.invalid.docx")
+	diags, err := tdoc.Lint()
+	if err != nil {
+		t.Fatalf("Lint: %s", err)
+	}
+	markers = nil
+	var warned bool
+	for _, d := range diags {
+		switch {
+		case strings.Contains(d.Message, "only text of paragraph"):
+			warned = true // marker among other text is not a marker
+		case d.Severity == docxplate.SeverityError:
+			markers = append(markers, d.Placeholder)
+		}
+	}
+	if strings.Join(markers, " ") != strings.Join(expected, " ") {
+		t.Fatalf("expected lint errors of %q, got: %v", expected, diags)
+	}
+	if !warned {
+		t.Fatalf("expected marker warning, got: %v", diags)
+	}
+}
+
+func TestBlocksLint(t *testing.T) {
+	tdoc, _ := docxplate.OpenTemplate("test-data/blocks.docx")
+
+	diags, err := tdoc.Lint()
+	if err != nil {
+		t.Fatalf("Lint: %s", err)
+	}
+	if len(diags) > 0 {
+		t.Fatalf("no diagnostics expected, got: %v", diags)
+	}
+}
+
+// contracts of each block tests
+func blockContracts() map[string]any {
+	return map[string]any{
+		"Company": "ACME",
+		"Contracts": []map[string]any{
+			{"Name": "A", "Signed": true, "Items": []map[string]any{{"Title": "x"}, {"Title": "y"}}},
+			{"Name": "B", "Signed": false, "Items": []map[string]any{{"Title": "z"}}},
+		},
+	}
+}
+
+func TestBlocksEach(t *testing.T) {
+	assertFixtureLines(t, "blocks.docx",
+		"{{#each Contracts}}",
+		"Contract: {{.Name}} of {{Company}}",
+		"Contract: item {{.Items.Title}}",
+		"{{#if .Signed}}",
+		"{{/each}}",
+	)
+
+	tdoc := renderFixture(t, "blocks.docx", blockContracts())
+
+	expected := []string{
+		"Contract: A of ACME",
+		"Contract: item x",
+		"Contract: item y",
+		"Contract: signed",
+		"Contract: B of ACME",
+		"Contract: item z",
+		"Contract: end",
+	}
+	lines := renderedLines(tdoc.Plaintext(), "Contract: ")
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected %q, got: %q", expected, lines)
+	}
+
+	buf, err := tdoc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %s", err)
+	}
+	xml := documentXMLFromBytes(t, buf)
+	if n := strings.Count(xml, `w:type="page"`); n != 2 {
+		t.Fatalf("expected page break for every contract, got: %d", n)
+	}
+	for _, s := range []string{"{{.", "{{Contracts", "{{#each", "{{/each}}", "{{Company}}"} {
+		if strings.Contains(xml, s) {
+			t.Fatalf("%s must be replaced: %s", s, tdoc.Plaintext())
+		}
+	}
+}
+
+func TestBlocksEachNested(t *testing.T) {
+	assertFixtureLines(t, "blocks.docx", "Items of: {{.Name}}", "{{#each .Items}}", "Items of: - {{.Title}}")
+
+	tdoc := renderFixture(t, "blocks.docx", blockContracts())
+
+	expected := []string{"Items of: A", "Items of: - x", "Items of: - y", "Items of: B", "Items of: - z"}
+	lines := renderedLines(tdoc.Plaintext(), "Items of: ")
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected %q, got: %q", expected, lines)
+	}
+}
+
+func TestBlocksEachValues(t *testing.T) {
+	assertFixtureLines(t, "blocks.docx", "{{#each Tags}}", "Tag: {{.}}", "Tag: end")
+
+	var tt = []struct {
+		params   map[string]any
+		expected []string
+	}{
+		{map[string]any{"Tags": []string{"red", "green"}}, []string{"Tag: red", "Tag: green", "Tag: end"}},
+		{map[string]any{"Tags": []string{}}, []string{"Tag: end"}},
+		{map[string]any{"Tags": nil}, []string{"Tag: end"}},
+		{map[string]any{}, []string{"Tag: end"}},
+	}
+
+	for _, tc := range tt {
+		tdoc := renderFixture(t, "blocks.docx", tc.params)
+
+		lines := renderedLines(tdoc.Plaintext(), "Tag: ")
+		if strings.Join(lines, "|") != strings.Join(tc.expected, "|") {
+			t.Fatalf("%v: expected %q, got: %q", tc.params, tc.expected, lines)
+		}
+	}
+}
+
+// TestBlocksEachMissing - block of unknown key is removed, unresolved with MissingKeyFail
+func TestBlocksEachMissing(t *testing.T) {
+	var tt = []struct {
+		policy  docxplate.MissingKey
+		markers []string
+	}{
+		{docxplate.MissingKeyKeep, nil},
+		{docxplate.MissingKeyBlank, nil},
+		{docxplate.MissingKeyFail, []string{"{{#each Contracts}}", "{{#each Contracts}}", "{{#each Tags}}"}},
+	}
+
+	for _, tc := range tt {
+		tdoc, _ := docxplate.OpenTemplate("test-data/blocks.docx")
+		params := map[string]any{"Insurance": false, "A": false, "Annex": false, "Insured": false}
+		err := tdoc.Render(params, docxplate.WithMissingKey(tc.policy))
+		if markers := unresolvedMarkers(err); strings.Join(markers, " ") != strings.Join(tc.markers, " ") {
+			t.Fatalf("policy %d: expected unresolved %q, got: %v", tc.policy, tc.markers, err)
+		}
+
+		lines := renderedLines(tdoc.Plaintext(), "Tag: ")
+		if strings.Join(lines, "|") != "Tag: end" {
+			t.Fatalf("policy %d: expected block removed, got: %q", tc.policy, lines)
+		}
+	}
+}
+
+// TestBlocksEachNotSlice - block of key not a slice is removed and reported
+func TestBlocksEachNotSlice(t *testing.T) {
+	for _, policy := range []docxplate.MissingKey{docxplate.MissingKeyKeep, docxplate.MissingKeyFail} {
+		tdoc, _ := docxplate.OpenTemplate("test-data/blocks.docx")
+		err := tdoc.Render(map[string]any{"Tags": "red"}, docxplate.WithMissingKey(policy))
+
+		var blockErr *docxplate.BlockError
+		if !errors.As(err, &blockErr) || blockErr.Part != "document" || blockErr.Key != "Tags" {
+			t.Fatalf("policy %d: expected *BlockError of Tags, got: %v", policy, err)
+		}
+		if lines := renderedLines(tdoc.Plaintext(), "Tag: "); strings.Join(lines, "|") != "Tag: end" {
+			t.Fatalf("policy %d: expected block removed, got: %q", policy, lines)
+		}
+	}
+}
+
+// TestBlocksYearKeys - number keys of maps are not slice indexes
+func TestBlocksYearKeys(t *testing.T) {
+	assertFixtureLines(t, "blocks.docx", "Year item: {{Years.2024.Items.Name}}")
+
+	items := []map[string]any{{"Name": "a"}, {"Name": "b"}}
+	for _, years := range []any{
+		map[string]any{"2024": map[string]any{"Items": items}},
+		map[int]any{2024: map[string]any{"Items": items}},
+	} {
+		tdoc := renderFixture(t, "blocks.docx", map[string]any{"Years": years})
+
+		expected := []string{"Year item: a", "Year item: b"}
+		if lines := renderedLines(tdoc.Plaintext(), "Year item: "); strings.Join(lines, "|") != strings.Join(expected, "|") {
+			t.Fatalf("%T: expected %q, got: %q", years, expected, lines)
+		}
+	}
+}
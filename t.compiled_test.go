@@ -0,0 +1,160 @@
+package docxplate_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bobiverse/docxplate"
+)
+
+// TestCompiledTemplateRender - every render must work on its own copy,
+// so compiled template renders the same way again and again
+func TestCompiledTemplateRender(t *testing.T) {
+	filenames := []string{
+		"tables.docx",
+		"lists.docx",
+		"header-footer.docx",
+		"user.template-no-images.docx",
+	}
+
+	for _, fname := range filenames {
+		ctpl, err := docxplate.CompileTemplate("test-data/" + fname)
+		if err != nil {
+			t.Fatalf("[%s] CompileTemplate: %s", fname, err)
+		}
+
+		for i, name := range []string{"Zed", "Alice", "Yan", "Alice"} {
+			user := User{Name: name, Friends: []*User{{Name: "Bob", Age: 28}, {Name: "Den", Age: 30}}}
+
+			// reference: the usual single render
+			tdoc, _ := docxplate.OpenTemplate("test-data/" + fname)
+			tdoc.Params(user)
+			expect := tdoc.Plaintext()
+
+			doc, err := ctpl.Render(user)
+			if err != nil {
+				t.Fatalf("[%s][%d] Render: %s", fname, i, err)
+			}
+			plaintext := doc.Plaintext()
+
+			// parts come in map order, so lines are compared not their order
+			if sortedLines(plaintext) != sortedLines(expect) {
+				t.Fatalf("[%s][%d] compiled render differs from single render:\n%s\n---\n%s", fname, i, plaintext, expect)
+			}
+
+			for _, other := range []string{"Zed", "Yan", "Alice"} {
+				if other != name && strings.Contains(plaintext, other) {
+					t.Fatalf("[%s][%d] value %q of previous render found:\n%s", fname, i, other, plaintext)
+				}
+			}
+			if !strings.Contains(plaintext, name) {
+				t.Fatalf("[%s][%d] value %q not found:\n%s", fname, i, name, plaintext)
+			}
+		}
+
+		// source template itself untouched
+		doc, _ := ctpl.Render(nil)
+		if !strings.Contains(doc.Plaintext(), "{{Name}}") {
+			t.Fatalf("[%s] compiled template lost its placeholders:\n%s", fname, doc.Plaintext())
+		}
+
+		if err := doc.ExportDocx("test-data/~test-compiled-" + fname); err != nil {
+			t.Fatalf("[%s] ExportDocx: %s", fname, err)
+		}
+	}
+}
+
+// sortedLines - lines of text sorted
+func sortedLines(s string) string {
+	lines := strings.Split(s, "\n")
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// TestCompiledTemplateParallel - one compiled template rendered
+// from multiple goroutines at once (run with `go test -race`)
+func TestCompiledTemplateParallel(t *testing.T) {
+	ctpl, err := docxplate.CompileTemplate("test-data/user.template-with-images.docx")
+	if err != nil {
+		t.Fatalf("CompileTemplate: %s", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			name := fmt.Sprintf("Alice-%d", i)
+			doc, err := ctpl.Render(User{
+				Name:       name,
+				ImageLocal: &docxplate.Image{Path: "images/avatar-1.png", Width: 25, Height: 25},
+			})
+			if err != nil {
+				t.Errorf("[%d] Render: %s", i, err)
+				return
+			}
+
+			for _, found := range reParallelName.FindAllString(doc.Plaintext(), -1) {
+				if found != name {
+					t.Errorf("[%d] rendered value %q of another render", i, found)
+				}
+			}
+			if _, err := doc.Bytes(); err != nil {
+				t.Errorf("[%d] Bytes: %s", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestCompiledTemplateFormatters - formatters registered on source template
+// after compile are not seen by compiled template rendering in parallel
+func TestCompiledTemplateFormatters(t *testing.T) {
+	assertFixtureLines(t, "formatters.custom.docx", "Shout: {{Name :shout}}")
+
+	tdoc, err := docxplate.OpenTemplate("test-data/formatters.custom.docx")
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+	tdoc.RegisterFormatter("shout", func(value any, args []string) (string, error) {
+		return strings.ToUpper(fmt.Sprint(value)) + "!", nil
+	})
+
+	ctpl, err := tdoc.Compile()
+	if err != nil {
+		t.Fatalf("Compile: %s", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 8; i++ {
+			tdoc.RegisterFormatter(fmt.Sprintf("late%d", i), func(value any, args []string) (string, error) {
+				return "", nil
+			})
+			tdoc.RegisterFormatter("shout", func(value any, args []string) (string, error) {
+				return "late", nil
+			})
+		}
+	}()
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			doc, err := ctpl.Render(map[string]any{"Name": "Alice"})
+			if err != nil {
+				t.Errorf("Render: %s", err)
+				return
+			}
+			if lines := renderedLines(doc.Plaintext(), "Shout: "); strings.Join(lines, "|") != "Shout: ALICE!" {
+				t.Errorf("formatter of compile time expected, got: %q", lines)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,153 @@
+package docxplate_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bobiverse/docxplate"
+)
+
+var reParallelName = regexp.MustCompile(`(Alice|Bob)-\d+`)
+
+// TestParallelTemplates - separate templates must render in parallel
+// without sharing any state (run with `go test -race`).
+// Every goroutine renders its own template with its own images,
+// local and downloaded, and checks nothing leaked from the others
+func TestParallelTemplates(t *testing.T) {
+	srv := httptest.NewServer(http.FileServer(http.Dir("images")))
+	defer srv.Close()
+
+	filenames := []string{
+		"tables.docx",
+		"lists.docx",
+		"user.template-with-images.docx",
+		"issue.51.docx",
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		fname := filenames[i%len(filenames)]
+
+		wg.Add(1)
+		go func(i int, fname string) {
+			defer wg.Done()
+
+			tdoc, err := docxplate.OpenTemplate("test-data/" + fname)
+			if err != nil {
+				t.Errorf("[%d][%s] OpenTemplate: %s", i, fname, err)
+				return
+			}
+
+			name := fmt.Sprintf("Alice-%d", i)
+			tdoc.Params(struct {
+				Name       string
+				ImageLocal *docxplate.Image
+				ImageURL   *docxplate.Image
+				Images     []*docxplate.Image
+				Friends    []*User
+			}{
+				Name:       name,
+				ImageLocal: &docxplate.Image{Path: "images/avatar-1.png", Width: 25, Height: 25},
+				ImageURL:   &docxplate.Image{URL: srv.URL + "/avatar-2.png", Width: 25, Height: 25},
+				Images: []*docxplate.Image{
+					{Path: "images/avatar-1.jpg", Width: 20, Height: 20},
+					{URL: srv.URL + "/avatar-1.gif", Width: 20, Height: 20},
+				},
+				Friends: []*User{{Name: fmt.Sprintf("Bob-%d", i), Age: 28}},
+			})
+
+			plaintext := tdoc.Plaintext()
+			for _, found := range reParallelName.FindAllString(plaintext, -1) {
+				if found != name && found != fmt.Sprintf("Bob-%d", i) {
+					t.Errorf("[%d][%s] rendered value %q of another template", i, fname, found)
+				}
+			}
+
+			buf, err := tdoc.Bytes()
+			if err != nil {
+				t.Errorf("[%d][%s] Bytes: %s", i, fname, err)
+				return
+			}
+			if _, err := zip.NewReader(bytes.NewReader(buf), int64(len(buf))); err != nil {
+				t.Errorf("[%d][%s] rendered docx is not a valid zip: %s", i, fname, err)
+			}
+		}(i, fname)
+	}
+	wg.Wait()
+}
+
+// TestParallelTemplatesImages - images of one template must never end up
+// in relationships or media of another template rendered at the same time
+func TestParallelTemplatesImages(t *testing.T) {
+	srv := httptest.NewServer(http.FileServer(http.Dir("images")))
+	defer srv.Close()
+
+	const count = 8
+	results := make([][]byte, count)
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			tdoc, err := docxplate.OpenTemplate("test-data/issue.51.docx")
+			if err != nil {
+				t.Errorf("[%d] OpenTemplate: %s", i, err)
+				return
+			}
+
+			// every template gets different number of images
+			var images []*docxplate.Image
+			for j := 0; j <= i; j++ {
+				images = append(images, &docxplate.Image{URL: srv.URL + "/avatar-3.png", Width: 20, Height: 20})
+			}
+			tdoc.Params(struct{ Images []*docxplate.Image }{Images: images})
+
+			if results[i], err = tdoc.Bytes(); err != nil {
+				t.Errorf("[%d] Bytes: %s", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, buf := range results {
+		if buf == nil {
+			continue
+		}
+		zr, err := zip.NewReader(bytes.NewReader(buf), int64(len(buf)))
+		if err != nil {
+			t.Fatalf("[%d] invalid zip: %s", i, err)
+		}
+
+		var media int
+		var rels string
+		for _, f := range zr.File {
+			if strings.HasPrefix(f.Name, "word/media/") {
+				media++
+			}
+			if f.Name != "word/_rels/document.xml.rels" {
+				continue
+			}
+			rc, _ := f.Open()
+			rbuf := new(bytes.Buffer)
+			_, _ = rbuf.ReadFrom(rc)
+			_ = rc.Close()
+			rels = rbuf.String()
+		}
+
+		if media != i+1 {
+			t.Errorf("[%d] media files: expected %d, got %d", i, i+1, media)
+		}
+		if n := strings.Count(rels, "relationships/image"); n != i+1 {
+			t.Errorf("[%d] image relationships: expected %d, got %d", i, i+1, n)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package docxplate_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bobiverse/docxplate"
+)
+
+func TestDefaults(t *testing.T) {
+	var tt = []struct {
+		text     string // paragraph of fixture
+		expected string
+	}{
+		{`Quoted: {{Phone|"not provided"}}.`, "Quoted: not provided."},
+		{"Curly quoted: {{Phone|“not provided”}}.", "Curly quoted: not provided."},
+		{"Formatter: {{Phone :default(n/a)}}.", "Formatter: n/a."},
+		{`Comma: {{Phone|"n/a, sorry"}}.`, "Comma: n/a, sorry."},
+		{`Empty quoted: {{Empty|"n/a"}}.`, "Empty quoted: n/a."},
+		{"Empty formatter: {{Empty :default(n/a)}}.", "Empty formatter: n/a."},
+		{`Value: {{Name|"n/a"}}.`, "Value: Alice."},
+		{`Value upper: {{Name|"n/a":upper}}.`, "Value upper: ALICE."},
+		{`Empty upper: {{Empty|"n/a":upper}}.`, "Empty upper: n/a."}, // default is written as is
+		{`Two: {{Phone|"n/a"}} and {{Name}}.`, "Two: n/a and Alice."},
+		{`Blank: {{Empty|""}}.`, "Blank: ."},
+		{`Escaped: \{{Phone|"n/a"}}.`, `Escaped: {{Phone|"n/a"}}.`},
+		{"Inline: {{Phones , :default(none)}}.", "Inline: none, 555."},
+		{"Inline empty: {{NoPhones , :default(none)}}.", "Inline empty: none."},
+		{"Rows: {{Phones :default(none)}}", "Rows: none\nRows: 555"},
+		{`Contacts: {{Contacts.Name}} {{Contacts.Phone|"-"}}`, "Contacts: Bob -\nContacts: Cecilia 555\nContacts: Den -"},
+	}
+
+	data := map[string]any{
+		"Name":     "Alice",
+		"Empty":    "",
+		"Phones":   []string{"", "555"},
+		"NoPhones": []string{},
+		"Contacts": []map[string]any{
+			{"Name": "Bob"},
+			{"Name": "Cecilia", "Phone": "555"},
+			{"Name": "Den", "Phone": ""},
+		},
+	}
+	tdoc := renderFixture(t, "defaults.docx", data, docxplate.WithMissingKey(docxplate.MissingKeyFail))
+
+	for _, tc := range tt {
+		assertFixtureLines(t, "defaults.docx", tc.text)
+		label, _, _ := strings.Cut(tc.text, ": ")
+
+		lines := renderedLines(tdoc.Plaintext(), label+": ")
+		if strings.Join(lines, "\n") != tc.expected {
+			t.Fatalf("%s: expected:\n%s\ngot:\n%s", label, tc.expected, strings.Join(lines, "\n"))
+		}
+	}
+}
+
+// TestDefaultsInspect - default is not taken for key, formatter or trigger
+func TestDefaultsInspect(t *testing.T) {
+	assertFixtureLines(t, "defaults.docx", `Trigger: {{Fax|"n/a":upper:empty:remove:row}}`)
+
+	tdoc, err := docxplate.OpenTemplate("test-data/defaults.docx")
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+
+	list, err := tdoc.Inspect()
+	if err != nil {
+		t.Fatalf("Inspect: %s", err)
+	}
+
+	var found bool
+	for _, info := range list {
+		if info.Key != "Fax" {
+			continue
+		}
+		found = true
+		if info.Formatter.String() != ":upper" || info.Trigger.String() != ":empty:remove:row" {
+			t.Fatalf("expected formatter :upper and trigger :empty:remove:row, got: %s %s", info.Formatter, info.Trigger)
+		}
+	}
+	if !found {
+		t.Fatalf("placeholder with default not found")
+	}
+
+	diags, err := tdoc.Lint()
+	if err != nil {
+		t.Fatalf("Lint: %s", err)
+	}
+	if len(diags) > 0 {
+		t.Fatalf("no diagnostics expected, got: %v", diags)
+	}
+}
@@ -0,0 +1,263 @@
+package docxplate_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bobiverse/docxplate"
+)
+
+// openDelimiters - test-data/delimiters.docx opened with given delimiters
+func openDelimiters(t *testing.T, open, close string) *docxplate.Template {
+	t.Helper()
+
+	tdoc, err := docxplate.OpenTemplate("test-data/delimiters.docx", docxplate.WithDelimiters(open, close))
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+	return tdoc
+}
+
+func TestDelimiters(t *testing.T) {
+	assertFixtureLines(t, "delimiters.docx",
+		"Square: Hi [[Name]]!",
+		"Square upper: [[Name :upper]]",
+		"Square inline: [[Friends.Name , ]]",
+		"Square default: {{Name}} [[Name]]",
+		"Guillemets: Hi «Name»!",
+		"Guillemets default: {{Name}} «Name»",
+		"Percent: Hi <%Name%>!",
+	)
+
+	var tt = []struct {
+		open, close string
+		expected    []string
+	}{
+		{"[[", "]]", []string{
+			"Square: Hi Alice!",
+			"Square upper: ALICE",
+			"Square inline: Bob, Cecilia, Den",
+			// default delimiters are plain text now
+			"Square default: {{Name}} Alice",
+		}},
+		{"«", "»", []string{
+			"Guillemets: Hi Alice!",
+			"Guillemets default: {{Name}} Alice",
+		}},
+		{"<%", "%>", []string{"Percent: Hi Alice!"}},
+	}
+
+	for _, tc := range tt {
+		tdoc := openDelimiters(t, tc.open, tc.close)
+		if err := tdoc.Render(vmergeUser()); err != nil {
+			t.Fatalf("%s%s: Render: %s", tc.open, tc.close, err)
+		}
+
+		plaintext := tdoc.Plaintext()
+		for _, expected := range tc.expected {
+			if !strings.Contains(plaintext, expected) {
+				t.Fatalf("%s%s: expected %q in:\n%s", tc.open, tc.close, expected, plaintext)
+			}
+		}
+	}
+}
+
+func TestDelimitersRows(t *testing.T) {
+	assertFixtureLines(t, "delimiters.docx", "Square rows: [[Friends.Name]]")
+
+	tdoc := openDelimiters(t, "[[", "]]")
+	if err := tdoc.Render(vmergeUser()); err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+
+	lines := renderedLines(tdoc.Plaintext(), "Square rows: ")
+	expected := []string{"Square rows: Bob", "Square rows: Cecilia", "Square rows: Den"}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected %q, got: %q", expected, lines)
+	}
+}
+
+// TestDelimitersBrokenRuns - placeholder split by Word into several runs
+// must be glued back for custom delimiters too
+func TestDelimitersBrokenRuns(t *testing.T) {
+	assertFixtureLines(t, "delimiters.docx", "Square broken: Hi [[Name]]!")
+
+	tdoc := openDelimiters(t, "[[", "]]")
+	if err := tdoc.Render(vmergeUser()); err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+
+	if plaintext := tdoc.Plaintext(); !strings.Contains(plaintext, "Square broken: Hi Alice!") {
+		t.Fatalf("broken placeholder not replaced:\n%s", plaintext)
+	}
+}
+
+// TestDelimitersBrokenRunsText - text ending with part of open delimiter
+// is no placeholder, runs after it keep their styles and lint is clean
+func TestDelimitersBrokenRunsText(t *testing.T) {
+	assertFixtureLines(t, "delimiters.docx", "Brace run: if (x) {bold2")
+
+	tdoc := renderFixture(t, "delimiters.docx", vmergeUser())
+	buf, err := tdoc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %s", err)
+	}
+	docXML := documentXMLFromBytes(t, buf)
+	if !strings.Contains(docXML, `Brace run: if (x) {</w:t>`) || !strings.Contains(docXML, `<w:b></w:b></w:rPr><w:t xml:space="preserve">bold2</w:t>`) {
+		t.Fatalf("runs must be kept apart:\n%s", docXML)
+	}
+
+	tdoc, err = docxplate.OpenTemplate("test-data/delimiters.docx")
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+	diags, err := tdoc.Lint()
+	if err != nil {
+		t.Fatalf("Lint: %s", err)
+	}
+	for _, diag := range diags {
+		if strings.Contains(diag.Location.Text, "Brace run") {
+			t.Fatalf("text linted: %s", diag)
+		}
+	}
+}
+
+func TestDelimitersAttributes(t *testing.T) {
+	tdoc := openDelimiters(t, "«", "»")
+	if err := tdoc.Render(vmergeUser()); err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+
+	buf, err := tdoc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %s", err)
+	}
+	if docXML := documentXMLFromBytes(t, buf); !strings.Contains(docXML, `w:val="Alice {{Name}}"`) {
+		t.Fatalf("attribute placeholder not replaced:\n%s", docXML)
+	}
+}
+
+func TestDelimitersInspect(t *testing.T) {
+	assertFixtureLines(t, "delimiters.docx", "Square inspect: [[Name :upper]] {{Age}}")
+
+	tdoc := openDelimiters(t, "[[", "]]")
+
+	infos, err := tdoc.Inspect()
+	if err != nil {
+		t.Fatalf("Inspect: %s", err)
+	}
+
+	var found bool
+	for _, info := range infos {
+		if info.Key == "Age" {
+			t.Fatalf("default delimiters must not be inspected: %+v", info)
+		}
+		if info.Placeholder == "[[Name :upper]]" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("[[Name :upper]] not inspected: %+v", infos)
+	}
+
+	ctpl, err := tdoc.Compile()
+	if err != nil {
+		t.Fatalf("Compile: %s", err)
+	}
+	doc, _ := ctpl.Render(map[string]any{"Age": 30})
+	if placeholders := doc.Placeholders(); len(placeholders) == 0 || !strings.HasPrefix(placeholders[0], "[[") {
+		t.Fatalf("expected placeholders in custom delimiters, got: %v", placeholders)
+	}
+}
+
+func TestDelimitersInvalid(t *testing.T) {
+	var tt = [][2]string{
+		{"", "]]"},
+		{"[[", ""},
+		{"%%", "%%"},
+		{"{", "{{"},
+	}
+
+	for _, tc := range tt {
+		if _, err := docxplate.OpenTemplate("test-data/delimiters.docx", docxplate.WithDelimiters(tc[0], tc[1])); err == nil {
+			t.Fatalf("%q %q: expected error", tc[0], tc[1])
+		}
+	}
+}
+
+// TestDelimitersRenderOnly - delimiters given to Render are not kept for the
+// next render, compiled template can't change them
+func TestDelimitersRenderOnly(t *testing.T) {
+	assertFixtureLines(t, "delimiters.docx", "Square: Hi [[Name]]!")
+
+	tdoc, err := docxplate.OpenTemplate("test-data/delimiters.docx")
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+
+	expected := []string{"Square: Hi Alice!", "Square: Hi [[Name]]!"}
+	for i, opts := range [][]docxplate.Option{{docxplate.WithDelimiters("[[", "]]")}, nil} {
+		if err := tdoc.Render(vmergeUser(), opts...); err != nil {
+			t.Fatalf("[%d] Render: %s", i, err)
+		}
+		if lines := renderedLines(tdoc.Plaintext(), "Square: "); len(lines) != 1 || lines[0] != expected[i] {
+			t.Fatalf("[%d] expected %q, got: %q", i, expected[i], lines)
+		}
+	}
+
+	ctpl, err := tdoc.Compile()
+	if err != nil {
+		t.Fatalf("Compile: %s", err)
+	}
+	if _, err := ctpl.Render(vmergeUser(), docxplate.WithDelimiters("[[", "]]")); !errors.Is(err, docxplate.ErrCompiledDelimiters) {
+		t.Fatalf("expected ErrCompiledDelimiters, got: %v", err)
+	}
+
+	// the same delimiters as of template are fine
+	ctpl, _ = docxplate.CompileTemplate("test-data/delimiters.docx", docxplate.WithDelimiters("[[", "]]"))
+	doc, err := ctpl.Render(vmergeUser(), docxplate.WithDelimiters("[[", "]]"))
+	if err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+	if lines := renderedLines(doc.Plaintext(), "Square: "); len(lines) != 1 || lines[0] != expected[0] {
+		t.Fatalf("expected %q, got: %q", expected[0], lines)
+	}
+}
+
+// TestDelimitersInspectAfterRender - Inspect, Lint and Compile between Render and
+// Bytes don't change options rendered document is written with
+func TestDelimitersInspectAfterRender(t *testing.T) {
+	assertFixtureLines(t, "delimiters.docx", `Square escaped: \[[Name]] [[Name]]`)
+
+	tdoc, err := docxplate.OpenTemplate("test-data/delimiters.docx")
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+	if err := tdoc.Render(vmergeUser(), docxplate.WithDelimiters("[[", "]]")); err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+
+	if _, err := tdoc.Inspect(); err != nil {
+		t.Fatalf("Inspect: %s", err)
+	}
+	if _, err := tdoc.Lint(); err != nil {
+		t.Fatalf("Lint: %s", err)
+	}
+	if _, err := tdoc.Compile(); err != nil {
+		t.Fatalf("Compile: %s", err)
+	}
+
+	expected := "Square escaped: [[Name]] Alice"
+	if lines := renderedLines(tdoc.Plaintext(), "Square escaped: "); len(lines) != 1 || lines[0] != expected {
+		t.Fatalf("expected %q, got: %q", expected, lines)
+	}
+
+	buf, err := tdoc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %s", err)
+	}
+	if docXML := documentXMLFromBytes(t, buf); !strings.Contains(docXML, expected) {
+		t.Fatalf("expected %q in:\n%s", expected, docXML)
+	}
+}
@@ -66,3 +66,23 @@ func BenchmarkLists1000(b *testing.B) {
 		log.Fatal(err)
 	}
 }
+
+func BenchmarkParams(b *testing.B) {
+	user := User{Name: "Walter", Friends: []*User{{Name: "Bob", Age: 28}}}
+
+	tdoc, _ := docxplate.OpenTemplate("test-data/user.template-no-images.docx")
+	for i := 0; i < b.N; i++ {
+		tdoc.Params(user)
+	}
+}
+
+func BenchmarkCompiledRender(b *testing.B) {
+	user := User{Name: "Walter", Friends: []*User{{Name: "Bob", Age: 28}}}
+
+	ctpl, _ := docxplate.CompileTemplate("test-data/user.template-no-images.docx")
+	for i := 0; i < b.N; i++ {
+		if _, err := ctpl.Render(user); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
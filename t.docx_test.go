@@ -3,7 +3,11 @@ package docxplate_test
 import (
 	"encoding/json"
 	"fmt"
+	"html"
 	"log"
+	"os"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
 
@@ -282,3 +286,67 @@ func TestWatermark(t *testing.T) {
 		log.Fatal(err)
 	}
 }
+
+// renderFixture - open test-data file and render it with given params
+func renderFixture(t *testing.T, fname string, v any, opts ...docxplate.Option) *docxplate.Template {
+	t.Helper()
+
+	tdoc, err := docxplate.OpenTemplate("test-data/" + fname)
+	if err != nil {
+		t.Fatalf("[%s] OpenTemplate: %s", fname, err)
+	}
+	if err := tdoc.Render(v, opts...); err != nil {
+		t.Fatalf("[%s] Render: %s", fname, err)
+	}
+	return tdoc
+}
+
+// renderedLines - lines of rendered plaintext starting with prefix
+func renderedLines(plaintext, prefix string) []string {
+	var lines []string
+	for _, line := range strings.Split(plaintext, "\n") {
+		if strings.HasPrefix(line, prefix) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+var (
+	reFixtureParagraph = regexp.MustCompile(`(?s)<w:p[ >].*?</w:p>`)
+	reFixtureText      = regexp.MustCompile(`(?s)<w:t(?: [^>]*)?>(.*?)</w:t>`)
+)
+
+// fixtureLines - text of every paragraph of test-data file as written,
+// read from its document.xml without the package
+func fixtureLines(t *testing.T, fname string) []string {
+	t.Helper()
+
+	buf, err := os.ReadFile("test-data/" + fname) // #nosec G304 - test fixture path
+	if err != nil {
+		t.Fatalf("[%s] ReadFile: %s", fname, err)
+	}
+
+	var lines []string
+	for _, p := range reFixtureParagraph.FindAllString(documentXMLFromBytes(t, buf), -1) {
+		var line strings.Builder
+		for _, m := range reFixtureText.FindAllStringSubmatch(p, -1) {
+			line.WriteString(html.UnescapeString(m[1]))
+		}
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// assertFixtureLines - every given line is a paragraph of test-data file,
+// so the placeholders test names are those it renders
+func assertFixtureLines(t *testing.T, fname string, lines ...string) {
+	t.Helper()
+
+	written := fixtureLines(t, fname)
+	for _, line := range lines {
+		if !slices.Contains(written, line) {
+			t.Fatalf("[%s] no such paragraph: %q", fname, line)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package docxplate_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bobiverse/docxplate"
+)
+
+// joinedErrors - all errors of joined render error
+func joinedErrors(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	if err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+func TestRenderErrorParams(t *testing.T) {
+	tdoc, _ := docxplate.OpenTemplate("test-data/vmerge.docx")
+
+	err := tdoc.Render(`{"Name": "Alice",`)
+	var paramsErr *docxplate.ParamsError
+	if !errors.As(err, &paramsErr) {
+		t.Fatalf("expected *ParamsError, got: %v", err)
+	}
+
+	ctpl, _ := tdoc.Compile()
+	if _, err := ctpl.Render([]byte(`[1, 2]`)); !errors.As(err, &paramsErr) {
+		t.Fatalf("compiled: expected *ParamsError, got: %v", err)
+	}
+}
+
+func TestRenderErrorImage(t *testing.T) {
+	assertFixtureLines(t, "errors.docx", "Name: {{Name}}", "{{Logo}}")
+	tdoc, _ := docxplate.OpenTemplate("test-data/errors.docx")
+
+	err := tdoc.Render(map[string]any{
+		"Name": "Alice",
+		"Logo": &docxplate.Image{Path: "images/no-such-image.png"},
+	})
+	var imgErr *docxplate.ImageError
+	if !errors.As(err, &imgErr) {
+		t.Fatalf("expected *ImageError, got: %v", err)
+	}
+	if imgErr.Part != "document" || imgErr.Key != "Logo" {
+		t.Fatalf("image error must point to document Logo, got: %s %s", imgErr.Part, imgErr.Key)
+	}
+
+	// the rest is rendered anyway
+	if !strings.Contains(tdoc.Plaintext(), "Name: Alice") {
+		t.Fatalf("params must be replaced despite image error:\n%s", tdoc.Plaintext())
+	}
+}
+
+func TestRenderErrorTrigger(t *testing.T) {
+	assertFixtureLines(t, "errors.docx", "{{Motto :empty:remove}}")
+	tdoc, _ := docxplate.OpenTemplate("test-data/errors.docx")
+
+	err := tdoc.Render(map[string]any{"Name": "Alice"})
+	var trErr *docxplate.TriggerError
+	if !errors.As(err, &trErr) {
+		t.Fatalf("expected *TriggerError, got: %v", err)
+	}
+	if trErr.Part != "document" || trErr.Key != "Motto" || trErr.Trigger != ":empty:remove" {
+		t.Fatalf("trigger error must point to document Motto :empty:remove, got: %s %s %s", trErr.Part, trErr.Key, trErr.Trigger)
+	}
+	if !errors.Is(err, docxplate.ErrTriggerParts) {
+		t.Fatalf("expected ErrTriggerParts, got: %v", err)
+	}
+}
+
+func TestRenderErrorPart(t *testing.T) {
+	// body of document.xml is not closed: </w:bdy>
+	assertFixtureLines(t, "errors.part.docx", "Name: {{Name}}")
+	tdoc, err := docxplate.OpenTemplate("test-data/errors.part.docx")
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+
+	err = tdoc.Render(map[string]any{"Name": "Alice"})
+	var partErr *docxplate.PartError
+	if !errors.As(err, &partErr) {
+		t.Fatalf("expected *PartError, got: %v", err)
+	}
+	if partErr.Part != "document" {
+		t.Fatalf("part error must point to document, got: %s", partErr.Part)
+	}
+
+	if _, err := tdoc.Compile(); !errors.As(err, &partErr) {
+		t.Fatalf("compile: expected *PartError, got: %v", err)
+	}
+}
+
+func TestRenderNoErrors(t *testing.T) {
+	tdoc, _ := docxplate.OpenTemplate("test-data/vmerge.docx")
+	if err := tdoc.Render(vmergeUser()); err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+}
@@ -0,0 +1,199 @@
+package docxplate_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bobiverse/docxplate"
+)
+
+func TestEscapedDelimiters(t *testing.T) {
+	var tt = []struct {
+		text     string // paragraph of fixture
+		expected string
+	}{
+		{`Escaped: \{{Name}}`, "{{Name}}"},
+		{`Escaped among: Hi {{Name}}, use \{{Name}} in template`, "Hi Alice, use {{Name}} in template"},
+		{`Escaped formatter: \{{Name :upper}} {{Name :upper}}`, "{{Name :upper}} ALICE"},
+		{`Escaped inline: \{{Friends.Name , }}`, "{{Friends.Name , }}"},
+		{`Escaped trigger: \{{Motto :empty:remove:paragraph}} stays`, "{{Motto :empty:remove:paragraph}} stays"},
+		{`Escaped around: \{{{{Name}}}}`, "{{Alice}}"},
+	}
+
+	tdoc := renderFixture(t, "escape.docx", vmergeUser())
+
+	plaintext := tdoc.Plaintext()
+	if strings.Contains(plaintext, "\\{{") {
+		t.Fatalf("escape mark left in:\n%s", plaintext)
+	}
+
+	buf, err := tdoc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %s", err)
+	}
+	docXML := documentXMLFromBytes(t, buf)
+
+	for _, tc := range tt {
+		assertFixtureLines(t, "escape.docx", tc.text)
+		label, _, _ := strings.Cut(tc.text, ": ")
+
+		expected := label + ": " + tc.expected
+		if lines := renderedLines(plaintext, label+": "); len(lines) != 1 || lines[0] != expected {
+			t.Fatalf("%s: expected %q, got: %q", label, expected, lines)
+		}
+		if !strings.Contains(docXML, tc.expected) {
+			t.Fatalf("%s: expected %q in saved document", label, tc.expected)
+		}
+	}
+}
+
+// TestEscapedDelimitersPlaceholders - escaped text is never listed as placeholder
+func TestEscapedDelimitersPlaceholders(t *testing.T) {
+	assertFixtureLines(t, "escape.docx", `Escaped unknown: \{{Escaped}} {{NoSuchKey}}`)
+	tdoc, err := docxplate.OpenTemplate("test-data/escape.docx")
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+
+	for _, placeholder := range tdoc.Placeholders() {
+		if strings.Contains(placeholder, "Escaped") {
+			t.Fatalf("escaped text listed as placeholder: %v", tdoc.Placeholders())
+		}
+	}
+
+	infos, err := tdoc.Inspect()
+	if err != nil {
+		t.Fatalf("Inspect: %s", err)
+	}
+	for _, info := range infos {
+		if info.Key == "Escaped" || info.Key == "Motto" {
+			t.Fatalf("escaped text inspected as placeholder: %+v", info)
+		}
+	}
+
+	diags, err := tdoc.Lint()
+	if err != nil {
+		t.Fatalf("Lint: %s", err)
+	}
+	for _, diag := range diags {
+		if strings.Contains(diag.Placeholder, "Escaped") {
+			t.Fatalf("escaped text linted: %s", diag)
+		}
+	}
+
+	err = tdoc.Render(vmergeUser(), docxplate.WithMissingKey(docxplate.MissingKeyFail))
+	var unresolvedErr *docxplate.UnresolvedError
+	if !errors.As(err, &unresolvedErr) {
+		t.Fatalf("expected *UnresolvedError, got: %v", err)
+	}
+	if len(unresolvedErr.Placeholders) != 1 || unresolvedErr.Placeholders[0].Key != "NoSuchKey" {
+		t.Fatalf("only {{NoSuchKey}} must be unresolved, got: %v", err)
+	}
+}
+
+// TestEscapedDelimitersBrokenRuns - escape mark split from placeholder by Word
+func TestEscapedDelimitersBrokenRuns(t *testing.T) {
+	assertFixtureLines(t, "escape.docx", `Escaped broken: Use \{{Name}}`)
+	tdoc := renderFixture(t, "escape.docx", vmergeUser())
+
+	if plaintext := tdoc.Plaintext(); !strings.Contains(plaintext, "Escaped broken: Use {{Name}}") {
+		t.Fatalf("escaped placeholder must be left as text:\n%s", plaintext)
+	}
+}
+
+// TestEscapedDelimitersBrokenRunsText - text ending with escape mark or part of
+// open delimiter is no placeholder, runs after it keep their styles and lint is clean
+func TestEscapedDelimitersBrokenRunsText(t *testing.T) {
+	assertFixtureLines(t, "escape.docx", `Path run: Folder C:\bold text`, "Brace run: if (x) {italic text")
+	tdoc := renderFixture(t, "escape.docx", vmergeUser())
+
+	buf, err := tdoc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %s", err)
+	}
+	docXML := documentXMLFromBytes(t, buf)
+	for _, run := range []string{
+		`Path run: Folder C:\</w:t>`,
+		`<w:b></w:b></w:rPr><w:t xml:space="preserve">bold text</w:t>`,
+		`Brace run: if (x) {</w:t>`,
+		`<w:i></w:i></w:rPr><w:t xml:space="preserve">italic text</w:t>`,
+	} {
+		if !strings.Contains(docXML, run) {
+			t.Fatalf("expected run %q kept apart:\n%s", run, docXML)
+		}
+	}
+
+	tdoc, err = docxplate.OpenTemplate("test-data/escape.docx")
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+	diags, err := tdoc.Lint()
+	if err != nil {
+		t.Fatalf("Lint: %s", err)
+	}
+	for _, diag := range diags {
+		if strings.Contains(diag.Location.Text, " run: ") {
+			t.Fatalf("text linted: %s", diag)
+		}
+	}
+}
+
+func TestEscapedDelimitersCustom(t *testing.T) {
+	assertFixtureLines(t, "delimiters.docx", `Square escaped: \[[Name]] [[Name]]`)
+	tdoc := openDelimiters(t, "[[", "]]")
+	if err := tdoc.Render(vmergeUser()); err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+
+	if plaintext := tdoc.Plaintext(); !strings.Contains(plaintext, "Square escaped: [[Name]] Alice") {
+		t.Fatalf("escaped placeholder must be left as text:\n%s", plaintext)
+	}
+}
+
+func TestEscapedDelimitersCompiled(t *testing.T) {
+	tdoc, err := docxplate.OpenTemplate("test-data/escape.docx")
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+	ctpl, err := tdoc.Compile()
+	if err != nil {
+		t.Fatalf("Compile: %s", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		doc, err := ctpl.Render(vmergeUser())
+		if err != nil {
+			t.Fatalf("Render: %s", err)
+		}
+		if plaintext := doc.Plaintext(); !strings.Contains(plaintext, "Hi Alice, use {{Name}} in template") {
+			t.Fatalf("escaped placeholder must be left as text:\n%s", plaintext)
+		}
+		for _, placeholder := range doc.Placeholders() {
+			if placeholder == "{{Name}}" {
+				t.Fatalf("escaped text listed as placeholder: %v", doc.Placeholders())
+			}
+		}
+	}
+}
+
+// TestEscapedDelimitersPrivateUse - private use chars of text and values
+// (icon fonts) are kept as they are, not taken for escaped delimiters
+func TestEscapedDelimitersPrivateUse(t *testing.T) {
+	assertFixtureLines(t, "escape.docx", "Private use: \uE000x \uE000\uE001 \\{{Name}} {{Name}}")
+
+	tdoc := renderFixture(t, "escape.docx", map[string]any{"Name": "\uE000\uE001Alice"})
+
+	expected := "Private use: \uE000x \uE000\uE001 {{Name}} \uE000\uE001Alice"
+	if lines := renderedLines(tdoc.Plaintext(), "Private use: "); len(lines) != 1 || lines[0] != expected {
+		t.Fatalf("expected %q, got: %q", expected, lines)
+	}
+
+	buf, err := tdoc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %s", err)
+	}
+	if docXML := documentXMLFromBytes(t, buf); !strings.Contains(docXML, expected) {
+		t.Fatalf("expected %q in saved document", expected)
+	}
+}
@@ -1,9 +1,13 @@
 package docxplate_test
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bobiverse/docxplate"
 )
@@ -48,3 +52,486 @@ func TestFormatters(t *testing.T) {
 		// success: just needs to be parsed without errors
 	}
 }
+
+func TestFormatDate(t *testing.T) {
+	signedAt := time.Date(2024, 3, 9, 22, 30, 0, 0, time.UTC)
+
+	var tt = []struct {
+		text     string // paragraph of fixture
+		expected string
+	}{
+		{"Date: {{SignedAt :date}}", "Date: 2024-03-09"},
+		{"Date layout: {{SignedAt :date(02.01.2006 15:04)}}", "Date layout: 09.03.2024 22:30"},
+		{"Date short: {{SignedAt :date(short)}}", "Date short: 2024-03-09"},
+		{"Date long: {{SignedAt :date(long)}}", "Date long: March 9, 2024"},
+		{"Date ISO: {{SignedAt :date(ISO)}}", "Date ISO: 2024-03-09T22:30:00Z"},
+		{"Date zone layout: {{SignedAt :date(Jan 2, 2006 15:04, Europe/Riga)}}", "Date zone layout: Mar 10, 2024 00:30"},
+		{"Date long zone: {{SignedAt :date(long, Europe/Riga)}}", "Date long zone: March 10, 2024"},
+		{"Date zone: {{SignedAt :date(Asia/Tokyo)}}", "Date zone: 2024-03-10"},
+		{"Date US: {{SignedAt :date(01/02/2006)}}", "Date US: 03/09/2024"},
+		{"Date weekday: {{SignedAt :date(Monday):upper}}", "Date weekday: SATURDAY"},
+		// other placeholders of the same key are not formatted
+		{"Date other: {{SignedAt :date(2006)}} {{Text :date(Jan 2006)}}", "Date other: 2024 Mar 2024"},
+		{"Dates: {{Dates :date(2 Jan)}}", "Dates: 1 Feb\nDates: 2 Mar"},
+		{"Dates inline: {{Dates , :date(2 Jan)}}", "Dates inline: 1 Feb, 2 Mar"},
+		{"Date empty: {{Empty :date(long)}}.", "Date empty: ."},
+	}
+
+	data := struct {
+		SignedAt time.Time
+		Text     string
+		Dates    []*time.Time
+		Empty    string
+	}{
+		SignedAt: signedAt,
+		Text:     "2024-03-01T10:00:00+02:00",
+		Dates:    []*time.Time{ptr(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)), ptr(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC))},
+	}
+	tdoc := renderFixture(t, "formatters.args.docx", data)
+
+	for _, tc := range tt {
+		assertFixtureLines(t, "formatters.args.docx", tc.text)
+		label, _, _ := strings.Cut(tc.text, ": ")
+
+		lines := renderedLines(tdoc.Plaintext(), label+": ")
+		if strings.Join(lines, "\n") != tc.expected {
+			t.Fatalf("%s: expected:\n%s\ngot:\n%s", label, tc.expected, strings.Join(lines, "\n"))
+		}
+	}
+}
+
+// TestFormatDateJSON - RFC 3339 dates of JSON and maps
+func TestFormatDateJSON(t *testing.T) {
+	assertFixtureLines(t, "formatters.args.docx", "Date UTC: {{SignedAt :date(long, UTC)}} {{Day :date(Mon)}}")
+
+	tdoc := renderFixture(t, "formatters.args.docx", `{"SignedAt": "2024-03-09T22:30:00-05:00", "Day": "2024-03-11"}`)
+
+	expected := "Date UTC: March 10, 2024 Mon"
+	if lines := renderedLines(tdoc.Plaintext(), "Date UTC: "); len(lines) != 1 || lines[0] != expected {
+		t.Fatalf("expected %q, got: %q", expected, lines)
+	}
+}
+
+// formatErrors - formats of all format errors of render error
+func formatErrors(t *testing.T, err error) []string {
+	t.Helper()
+
+	var formats []string
+	for _, err := range joinedErrors(err) {
+		var formatErr *docxplate.FormatError
+		if errors.As(err, &formatErr) {
+			if formatErr.Part != "document" {
+				t.Fatalf("format error must point to document, got: %s", formatErr)
+			}
+			formats = append(formats, formatErr.Format)
+		}
+	}
+	return formats
+}
+
+// renderInvalidFormatters - render test-data/formatters.invalid.docx, formats
+// of failed placeholders are returned
+func renderInvalidFormatters(t *testing.T) []string {
+	t.Helper()
+
+	tdoc, err := docxplate.OpenTemplate("test-data/formatters.invalid.docx")
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+	err = tdoc.Render(map[string]any{"SignedAt": time.Now(), "Text": "ten", "Total": 10, "Name": "Alice", "Count": 4})
+	return formatErrors(t, err)
+}
+
+// assertFormatErrors - every expected format is among failed ones
+func assertFormatErrors(t *testing.T, formats []string, expected ...string) {
+	t.Helper()
+
+	for _, format := range expected {
+		if !slices.Contains(formats, format) {
+			t.Fatalf("%s: expected *FormatError, got: %q", format, formats)
+		}
+	}
+}
+
+func TestFormatDateErrors(t *testing.T) {
+	assertFixtureLines(t, "formatters.invalid.docx", "{{SignedAt :date(long, Europe/Nowhere)}}", "{{Text :date}}")
+
+	assertFormatErrors(t, renderInvalidFormatters(t), ":date(long, Europe/Nowhere)", ":date")
+
+	// invalid time zone is found by lint too
+	tdoc, _ := docxplate.OpenTemplate("test-data/formatters.invalid.docx")
+	diags, err := tdoc.Lint()
+	if err != nil {
+		t.Fatalf("Lint: %s", err)
+	}
+	var found bool
+	for _, diag := range diags {
+		found = found || strings.Contains(diag.Message, "invalid formatter [:date(long, Europe/Nowhere)]")
+	}
+	if !found {
+		t.Fatalf("lint must report invalid time zone: %v", diags)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// TestFormatCurrencyLocales - currency symbol before or after amount by locale,
+// language with region wins over language
+func TestFormatCurrencyLocales(t *testing.T) {
+	var tt = []struct {
+		locale   string
+		code     string
+		expected string
+	}{
+		{"en", "USD", "$1,234.56"},
+		{"en-US", "EUR", "€1,234.56"},
+		{"lv", "EUR", "1 234,56 €"},
+		{"de", "EUR", "1.234,56 €"},
+		{"de-AT", "EUR", "€ 1 234,56"},
+		{"fr", "EUR", "1 234,56 €"},
+		{"fr-CH", "EUR", "1 234,56 €"},
+		{"it-CH", "EUR", "€ 1’234.56"},
+		{"es", "EUR", "1.234,56 €"},
+		{"es-MX", "MXN", "$1,234.56"},
+		{"nl", "EUR", "€ 1.234,56"},
+		{"nl-BE", "EUR", "€ 1.234,56"},
+		{"pt", "BRL", "R$ 1.234,56"},
+		{"pt-BR", "BRL", "R$ 1.234,56"},
+		{"pt-PT", "EUR", "1 234,56 €"},
+		{"pl", "PLN", "1 234,56 zł"},
+	}
+
+	for _, tc := range tt {
+		assertFixtureLines(t, "formatters.args.docx", "Currency "+tc.code+": {{Total :currency("+tc.code+")}}")
+		tdoc := renderFixture(t, "formatters.args.docx", map[string]any{"Total": 1234.56}, docxplate.WithLocale(tc.locale))
+
+		// no-break spaces of locales written as spaces here
+		label := "Currency " + tc.code + ": "
+		lines := renderedLines(strings.ReplaceAll(tdoc.Plaintext(), "\u00a0", " "), label)
+		if strings.Join(lines, "\n") != label+tc.expected {
+			t.Errorf("[%s]: expected %q, got: %q", tc.locale, label+tc.expected, lines)
+		}
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	var tt = []struct {
+		text     string // paragraph of fixture
+		locale   string
+		expected string
+	}{
+		{"Number: {{Total :number}}", "", "Number: 1,234.567"},
+		{"Number 2: {{Total :number(2)}}", "", "Number 2: 1,234.57"},
+		{"Number 2: {{Total :number(2)}}", "lv", "Number 2: 1 234,57"},
+		{"Number de: {{Total :number(0, de)}}", "lv", "Number de: 1.235"},
+		{"Currency EUR: {{Total :currency(EUR)}}", "lv", "Currency EUR: 1 234,57 €"},
+		{"Currency en-US: {{Total :currency(USD, en-US)}}", "lv", "Currency en-US: $1,234.57"},
+		{"Currency JPY: {{Count :currency(JPY)}}", "en", "Currency JPY: ¥1,000,000"},
+		{"Percent: {{Rate :percent}}", "", "Percent: 26%"},
+		{"Percent de: {{Rate :percent(1, de)}}", "", "Percent de: 25,6 %"},
+		{"Count: {{Count :number}}", "en-US", "Count: 1,000,000"},
+		{"Amounts: {{Amounts :currency(EUR)}}", "lv", "Amounts: 10,00 €\nAmounts: 2 500,50 €"},
+		{"Amounts inline: {{Amounts ; :number(1)}}", "", "Amounts inline: 10.0; 2,500.5"},
+		{"Number empty: {{Empty :number(2)}}.", "", "Number empty: ."},
+	}
+
+	for _, tc := range tt {
+		assertFixtureLines(t, "formatters.args.docx", tc.text)
+		label, _, _ := strings.Cut(tc.text, ": ")
+
+		data := map[string]any{
+			"Total":   1234.567,
+			"Count":   1000000,
+			"Rate":    0.256,
+			"Amounts": []any{10, "2500.5"},
+			"Empty":   "",
+		}
+
+		var opts []docxplate.Option
+		if tc.locale != "" {
+			opts = append(opts, docxplate.WithLocale(tc.locale))
+		}
+		tdoc := renderFixture(t, "formatters.args.docx", data, opts...)
+
+		// no-break spaces of locales written as spaces here
+		lines := renderedLines(strings.ReplaceAll(tdoc.Plaintext(), "\u00a0", " "), label+": ")
+		if expected := strings.ReplaceAll(tc.expected, "\u00a0", " "); strings.Join(lines, "\n") != expected {
+			t.Fatalf("%s [%s]: expected:\n%q\ngot:\n%q", label, tc.locale, tc.expected, strings.Join(lines, "\n"))
+		}
+	}
+}
+
+// TestFormatNumberJSON - JSON numbers are formatted as written, locale of template
+func TestFormatNumberJSON(t *testing.T) {
+	assertFixtureLines(t, "formatters.args.docx", "Currency EUR: {{Total :currency(EUR)}}")
+
+	tdoc, err := docxplate.OpenTemplate("test-data/formatters.args.docx", docxplate.WithLocale("lv"))
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+	if err := tdoc.Render(`{"Total": 1234.56}`); err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+
+	// no-break spaces of locales written as spaces here
+	expected := "Currency EUR: 1 234,56 €"
+	if lines := renderedLines(strings.ReplaceAll(tdoc.Plaintext(), "\u00a0", " "), "Currency EUR: "); len(lines) != 1 || lines[0] != expected {
+		t.Fatalf("expected %q, got: %q", expected, lines)
+	}
+}
+
+func TestFormatNumberErrors(t *testing.T) {
+	assertFixtureLines(t, "formatters.invalid.docx",
+		"{{Total :number(-1)}}",
+		"{{Total :number(2, no such locale)}}",
+		"{{Total :currency}}",
+		"{{Total :currency(XYZ)}}",
+		"{{Text :number}}",
+	)
+
+	if _, err := docxplate.OpenTemplate("test-data/vmerge.docx", docxplate.WithLocale("not a locale")); err == nil {
+		t.Fatalf("invalid locale must fail")
+	}
+
+	assertFormatErrors(t, renderInvalidFormatters(t),
+		":number(-1)",
+		":number(2, no such locale)",
+		":currency",
+		":currency(XYZ)",
+		":number",
+	)
+}
+
+func TestFormatPipeline(t *testing.T) {
+	var tt = []struct {
+		text     string // paragraph of fixture
+		expected string
+	}{
+		{"Chain truncate: {{Name :trim:truncate(5):upper}}.", "Chain truncate: LOREM."},
+		{"Chain title: {{Name :trim:upper:title}}.", "Chain title: Lorem Ipsum Dolor."},
+		{"Chain upper: {{Name :title:upper:trim}}.", "Chain upper: LOREM IPSUM DOLOR."},
+		{"Chain ellipsis: {{Name :trim:truncate(11, …)}}.", "Chain ellipsis: lorem ipsum…."},
+		{"Chain short: {{Name :trim:truncate(50, …)}}.", "Chain short: lorem ipsum dolor."},
+		{"Chain number: {{Total :number(1):truncate(3)}}.", "Chain number: 1,2."},
+		{"Chain trigger: {{Name :trim:capitalize:empty:remove:row}}.", "Chain trigger: Lorem ipsum dolor."},
+		{"Chain empty: {{Empty :trim:upper:empty:remove:row}}.", ""},
+		{"Chain inline: {{Tags , :upper:truncate(2)}}.", "Chain inline: AL, BE, GA."},
+		{"Chain rows: {{Tags :trim:title}}", "Chain rows: Alpha\nChain rows: Beta\nChain rows: Gamma"},
+	}
+
+	data := map[string]any{
+		"Name":  "  lorem ipsum dolor ",
+		"Total": 1234.5,
+		"Empty": "",
+		"Tags":  []string{"alpha", "beta", "gamma"},
+	}
+	tdoc := renderFixture(t, "formatters.args.docx", data)
+
+	for _, tc := range tt {
+		assertFixtureLines(t, "formatters.args.docx", tc.text)
+		label, _, _ := strings.Cut(tc.text, ": ")
+
+		lines := renderedLines(tdoc.Plaintext(), label+": ")
+		if strings.Join(lines, "\n") != tc.expected {
+			t.Fatalf("%s: expected:\n%s\ngot:\n%s", label, tc.expected, strings.Join(lines, "\n"))
+		}
+	}
+}
+
+// TestFormatPipelineParsed - chain is parsed apart from trigger and vmerge mark
+func TestFormatPipelineParsed(t *testing.T) {
+	p := docxplate.NewParam("Name")
+	p.Formatter = docxplate.NewFormatter([]byte(":trim:empty:truncate(20, …):remove:vmerge:upper:row"))
+	p.Trigger = docxplate.NewParamTrigger([]byte(":trim:empty:truncate(20, …):remove:vmerge:upper:row"))
+
+	if s := p.Formatter.String(); s != ":trim:truncate(20, …):upper" {
+		t.Fatalf("expected formatter :trim:truncate(20, …):upper, got: %s", s)
+	}
+	if len(p.Formatter.Steps) != 3 || p.Formatter.Steps[1].Format != docxplate.FormatTruncate {
+		t.Fatalf("expected 3 steps with truncate second, got: %+v", p.Formatter.Steps)
+	}
+	if args := p.Formatter.Steps[1].Args; len(args) != 2 || args[0] != "20" || args[1] != "…" {
+		t.Fatalf("expected truncate args [20 …], got: %q", args)
+	}
+	if p.Formatter.Format != docxplate.FormatUpper {
+		t.Fatalf("Format must be the last format of chain, got: %s", p.Formatter.Format)
+	}
+	if s := p.Trigger.String(); s != ":empty:remove:row" {
+		t.Fatalf("expected trigger :empty:remove:row, got: %s", s)
+	}
+}
+
+// TestFormatPipelineVMerge - chain with vmerge mark in multiplied row
+func TestFormatPipelineVMerge(t *testing.T) {
+	assertFixtureLines(t, "formatters.args.docx", "Merged: {{Friends.Name}}", "{{Name :lower:truncate(3):vmerge}}")
+
+	tdoc := renderFixture(t, "formatters.args.docx", vmergeUser())
+
+	// rendered table only
+	_, plaintext, _ := strings.Cut(tdoc.Plaintext(), "Merged: ")
+	if !strings.Contains(plaintext, "\nali\n") || strings.Contains(plaintext, "Alice") {
+		t.Fatalf("formatted merged value expected, got:\n%s", plaintext)
+	}
+}
+
+func TestFormatPipelineErrors(t *testing.T) {
+	assertFixtureLines(t, "formatters.invalid.docx", "{{Name :upper:truncate(many)}}")
+
+	assertFormatErrors(t, renderInvalidFormatters(t), ":upper:truncate(many)")
+
+	tdoc, _ := docxplate.OpenTemplate("test-data/formatters.invalid.docx")
+	diags, err := tdoc.Lint()
+	if err != nil {
+		t.Fatalf("Lint: %s", err)
+	}
+	var found bool
+	for _, diag := range diags {
+		found = found || strings.Contains(diag.Message, "invalid formatter [:truncate(many)]")
+	}
+	if !found {
+		t.Fatalf("lint must report invalid truncate step: %v", diags)
+	}
+}
+
+func TestRegisterFormatter(t *testing.T) {
+	// IBAN in groups: :iban(size, separator)
+	docxplate.RegisterFormatter("iban", func(value any, args []string) (string, error) {
+		size, sep := 4, " "
+		if len(args) > 0 {
+			size, _ = strconv.Atoi(args[0])
+		}
+		if len(args) > 1 {
+			sep = args[1]
+		}
+		s := strings.ReplaceAll(fmt.Sprint(value), " ", "")
+		var groups []string
+		for len(s) > size {
+			groups, s = append(groups, s[:size]), s[size:]
+		}
+		return strings.Join(append(groups, s), sep), nil
+	})
+	docxplate.RegisterFormatter(":mask", func(value any, args []string) (string, error) {
+		return "***", nil
+	})
+	docxplate.RegisterFormatter("words", func(value any, args []string) (string, error) {
+		if n, ok := value.(int); ok && n == 3 {
+			return "three", nil
+		}
+		return "", fmt.Errorf("no words for %v (%T)", value, value)
+	})
+
+	var tt = []struct {
+		text     string // paragraph of fixture
+		expected string
+	}{
+		{"IBAN: {{IBAN :iban}}", "IBAN: LV80 BANK 0000 4351 9500 1"},
+		{"IBAN groups: {{IBAN :iban(5, -)}}", "IBAN groups: LV80B-ANK00-00435-19500-1"},
+		{"IBAN chain: {{IBAN :lower:iban:upper}}", "IBAN chain: LV80 BANK 0000 4351 9500 1"},
+		{"IBAN short: {{IBAN :truncate(4):iban(2, .)}}", "IBAN short: LV.80"},
+		{"Mask: {{Code :mask}}", "Mask: ###"}, // template formatter wins
+		{"Words: {{Count :words:upper}}", "Words: THREE"},
+		{"Friend mask: {{Friends.Code :mask}}", "Friend mask: ###\nFriend mask: ###"},
+	}
+
+	data := map[string]any{
+		"IBAN":    "LV80BANK0000435195001",
+		"Code":    "010203-12345",
+		"Count":   3,
+		"Friends": []map[string]any{{"Code": "A"}, {"Code": "B"}},
+	}
+
+	tdoc, err := docxplate.OpenTemplate("test-data/formatters.custom.docx")
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+	tdoc.RegisterFormatter("mask", func(value any, args []string) (string, error) {
+		return strings.Repeat("#", 3), nil
+	})
+	tdoc.RegisterFormatter("shout", func(value any, args []string) (string, error) {
+		return strings.ToUpper(fmt.Sprint(value)) + "!", nil
+	})
+	if err := tdoc.Render(data); err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+
+	for _, tc := range tt {
+		assertFixtureLines(t, "formatters.custom.docx", tc.text)
+		label, _, _ := strings.Cut(tc.text, ": ")
+
+		lines := renderedLines(tdoc.Plaintext(), label+": ")
+		if strings.Join(lines, "\n") != tc.expected {
+			t.Fatalf("%s: expected:\n%s\ngot:\n%s", label, tc.expected, strings.Join(lines, "\n"))
+		}
+	}
+
+	// registered for all templates
+	f := docxplate.NewFormatter([]byte(":iban"))
+	if s := string(f.ApplyFormat(f.Format, []byte("LV80BANK"))); s != "LV80 BANK" {
+		t.Fatalf("ApplyFormat must use registered formatter, got: %s", s)
+	}
+
+	// failed custom formatter
+	assertFixtureLines(t, "formatters.invalid.docx", "{{Count :words}}")
+	assertFormatErrors(t, renderInvalidFormatters(t), ":words")
+}
+
+func TestRegisterFormatterLint(t *testing.T) {
+	assertFixtureLines(t, "formatters.invalid.docx", "{{Code :secret}}")
+
+	tdoc, err := docxplate.OpenTemplate("test-data/formatters.invalid.docx")
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+
+	unknown := func() bool {
+		diags, err := tdoc.Lint()
+		if err != nil {
+			t.Fatalf("Lint: %s", err)
+		}
+		for _, diag := range diags {
+			if strings.Contains(diag.Message, "unknown formatter [:secret]") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !unknown() {
+		t.Fatalf("unregistered formatter must be reported")
+	}
+	tdoc.RegisterFormatter("secret", func(value any, args []string) (string, error) {
+		return "", nil
+	})
+	if unknown() {
+		t.Fatalf("formatter registered for template must be known")
+	}
+}
+
+func TestRegisterFormatterInvalid(t *testing.T) {
+	fn := func(value any, args []string) (string, error) { return "", nil }
+
+	var tt = []struct {
+		name string
+		fn   docxplate.FormatFunc
+	}{
+		{"upper", fn},
+		{":empty", fn},
+		{"vmerge", fn},
+		{"two words", fn},
+		{"", fn},
+		{"nilfunc", nil},
+	}
+
+	for _, tc := range tt {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("[%s] RegisterFormatter must panic", tc.name)
+				}
+			}()
+			docxplate.RegisterFormatter(tc.name, tc.fn)
+		}()
+	}
+}
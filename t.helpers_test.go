@@ -104,3 +104,33 @@ func TestInsertChildAfterHeadResetsPriv(t *testing.T) {
 		t.Fatalf("parent.childLast = %p, want nil (parent has no children left)", parent.childLast)
 	}
 }
+
+// TestCompiledPartsUnchanged - renders of compiled template work on copies,
+// parts parsed on compile stay as they were
+func TestCompiledPartsUnchanged(t *testing.T) {
+	ctpl, err := CompileTemplate("test-data/user.template-no-images.docx")
+	if err != nil {
+		t.Fatalf("CompileTemplate: %s", err)
+	}
+
+	before := map[string][]byte{}
+	for fname, xnode := range ctpl.parts {
+		before[fname] = structToXMLBytes(xnode)
+	}
+
+	for _, v := range []any{
+		map[string]any{"Name": "Alice", "Friends": []map[string]any{{"Name": "Bob"}, {"Name": "Den"}}},
+		map[string]any{"Name": "Zed", "Friends": []any{}},
+		nil,
+	} {
+		if _, err := ctpl.Render(v); err != nil {
+			t.Fatalf("Render: %s", err)
+		}
+	}
+
+	for fname, xnode := range ctpl.parts {
+		if !bytes.Equal(before[fname], structToXMLBytes(xnode)) {
+			t.Fatalf("[%s] compiled part changed by render", fname)
+		}
+	}
+}
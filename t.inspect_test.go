@@ -0,0 +1,176 @@
+package docxplate_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bobiverse/docxplate"
+)
+
+func TestInspect(t *testing.T) {
+	tdoc, _ := docxplate.OpenTemplate("test-data/tables.docx")
+	before, _ := tdoc.Bytes()
+
+	list, err := tdoc.Inspect()
+	if err != nil {
+		t.Fatalf("Inspect: %s", err)
+	}
+
+	// no side effects
+	after, _ := tdoc.Bytes()
+	if documentXMLFromBytes(t, before) != documentXMLFromBytes(t, after) {
+		t.Fatalf("Inspect must not change template")
+	}
+
+	byPlaceholder := map[string]docxplate.PlaceholderInfo{}
+	for _, info := range list {
+		if info.Part != "document" {
+			t.Errorf("%s: expected document part, got %s", info.Placeholder, info.Part)
+		}
+		byPlaceholder[info.Placeholder] = info
+	}
+
+	cases := []struct {
+		placeholder string
+		key         string
+		kind        docxplate.LocationKind
+		expand      docxplate.ExpandKind
+		trigger     string
+	}{
+		{"{{Friends.1.Name}}", "Friends.1.Name", docxplate.LocationListItem, docxplate.ExpandSingle, ""},
+		{"{{Name}}", "Name", docxplate.LocationListItem, docxplate.ExpandSingle, ""},
+		{"{{NotReplacable}}", "NotReplacable", docxplate.LocationParagraph, docxplate.ExpandSingle, ""},
+		// slice or struct is known with data only
+		{"{{Friends.Age}}", "Friends.Age", docxplate.LocationTableCell, docxplate.ExpandUnknown, ""},
+		{"{{Friends.Name :empty:remove:table}}", "Friends.Name", docxplate.LocationTableCell, docxplate.ExpandUnknown, ":empty:remove:table"},
+		{"{{NotReplacable , }}", "NotReplacable", docxplate.LocationParagraph, docxplate.ExpandInline, ""},
+	}
+	for _, c := range cases {
+		info, ok := byPlaceholder[c.placeholder]
+		if !ok {
+			t.Errorf("%s not found", c.placeholder)
+			continue
+		}
+		if info.Key != c.key {
+			t.Errorf("%s: key expected %q, got %q", c.placeholder, c.key, info.Key)
+		}
+		if info.Kind != c.kind {
+			t.Errorf("%s: location expected %s, got %s", c.placeholder, c.kind, info.Kind)
+		}
+		if info.Expand != c.expand {
+			t.Errorf("%s: expand expected %s, got %s", c.placeholder, c.expand, info.Expand)
+		}
+		if info.Trigger.String() != c.trigger {
+			t.Errorf("%s: trigger expected %q, got %q", c.placeholder, c.trigger, info.Trigger.String())
+		}
+	}
+
+	if info := byPlaceholder["{{NotReplacable , }}"]; info.Separator != "," {
+		t.Errorf("separator expected \",\", got %q", info.Separator)
+	}
+
+	if info := byPlaceholder["{{Friends.Age}}"]; info.Text != "Age: {{Friends.Age}} y/o" {
+		t.Errorf("location text expected whole paragraph, got %q", info.Text)
+	}
+}
+
+func TestInspectAttributes(t *testing.T) {
+	ctpl, _ := docxplate.CompileTemplate("test-data/watermark.docx")
+
+	var found int
+	for _, info := range ctpl.Inspect() {
+		if info.Part != "header1" || info.Kind != docxplate.LocationAttribute {
+			continue
+		}
+		found++
+		if info.Expand != docxplate.ExpandSingle {
+			t.Errorf("%s: attribute placeholder never expands, got %s", info.Placeholder, info.Expand)
+		}
+	}
+	if found != 2 {
+		t.Fatalf("expected 2 watermark attribute placeholders in header1, got %d", found)
+	}
+}
+
+func TestPlaceholdersNoSideEffects(t *testing.T) {
+	tdoc, _ := docxplate.OpenTemplate("test-data/tables.docx")
+	before, _ := tdoc.Bytes()
+
+	if len(tdoc.Placeholders()) == 0 {
+		t.Fatalf("placeholders expected")
+	}
+
+	after, _ := tdoc.Bytes()
+	if documentXMLFromBytes(t, before) != documentXMLFromBytes(t, after) {
+		t.Fatalf("Placeholders must not change template")
+	}
+}
+
+// TestInspectDottedKeys - without data dotted keys are rows only next to
+// loop key of the same slice, they may be struct fields as well
+func TestInspectDottedKeys(t *testing.T) {
+	assertFixtureLines(t, "loops.docx", "Number: {{Friends.#number}}. {{Friends.Name}} ({{Friends.#index}} of {{Friends.#count}})")
+	assertFixtureLines(t, "nested.docx",
+		"Parents: {{Friends.Name}} / {{Friends.Friends.Name :upper}} / {{Friends.Friends.Friends.Name}}",
+		"Signature: {{Signature}}",
+		"Offices: {{Offices.HQ.Street}}, {{Offices.HQ.City}}",
+	)
+
+	var tt = []struct {
+		fname    string
+		sample   any
+		expected map[string]docxplate.ExpandKind
+	}{
+		{"loops.docx", nil, map[string]docxplate.ExpandKind{
+			"{{Friends.#number}}": docxplate.ExpandRow,
+			"{{Friends.Name}}":    docxplate.ExpandRow,
+		}},
+		{"nested.docx", nil, map[string]docxplate.ExpandKind{
+			"{{Friends.Name}}":      docxplate.ExpandUnknown,
+			"{{Offices.HQ.Street}}": docxplate.ExpandUnknown,
+		}},
+		{"nested.docx", nestedUser(), map[string]docxplate.ExpandKind{
+			"{{Friends.Name}}":                docxplate.ExpandRow,
+			"{{Friends.Friends.Name :upper}}": docxplate.ExpandRow,
+			"{{Offices.HQ.Street}}":           docxplate.ExpandUnknown, // not in sample
+			"{{Signature}}":                   docxplate.ExpandSingle,
+		}},
+		{"nested.docx", map[string]any{"Offices": map[string]any{"HQ": map[string]any{"Street": "Main 1"}}}, map[string]docxplate.ExpandKind{
+			"{{Offices.HQ.Street}}": docxplate.ExpandSingle,
+			"{{Friends.Name}}":      docxplate.ExpandUnknown,
+		}},
+	}
+
+	for _, tc := range tt {
+		tdoc, err := docxplate.OpenTemplate("test-data/" + tc.fname)
+		if err != nil {
+			t.Fatalf("[%s] OpenTemplate: %s", tc.fname, err)
+		}
+		list, err := tdoc.Inspect(tc.sample)
+		if err != nil {
+			t.Fatalf("[%s] Inspect: %s", tc.fname, err)
+		}
+
+		found := map[string]bool{}
+		for _, info := range list {
+			expand, ok := tc.expected[info.Placeholder]
+			if !ok {
+				continue
+			}
+			found[info.Placeholder] = true
+			if info.Expand != expand {
+				t.Errorf("[%s] %s with sample %v: expand expected %s, got %s", tc.fname, info.Placeholder, tc.sample != nil, expand, info.Expand)
+			}
+		}
+		if len(found) != len(tc.expected) {
+			t.Errorf("[%s] expected placeholders %v, found %v", tc.fname, tc.expected, found)
+		}
+	}
+
+	// sample is collected as data of Render
+	tdoc, _ := docxplate.OpenTemplate("test-data/nested.docx")
+	var paramsErr *docxplate.ParamsError
+	if _, err := tdoc.Inspect(`{"Friends": [`); !errors.As(err, &paramsErr) {
+		t.Fatalf("expected *ParamsError of invalid sample, got: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package docxplate_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bobiverse/docxplate"
+)
+
+func TestLint(t *testing.T) {
+	cases := []struct {
+		text     string
+		severity docxplate.Severity
+		message  string
+	}{
+		{"{{Name :empty:delete:row}}", docxplate.SeverityError, "unknown trigger word [:delete]"},
+		{"{{Name :empty:remove}}", docxplate.SeverityError, "invalid trigger [:empty:remove]"},
+		{"{{Name :uper}}", docxplate.SeverityError, "unknown formatter [:uper]"},
+		{"{{Name :vmerge}}", docxplate.SeverityWarning, ":vmerge outside of table row"},
+		{"{{Items.Name :limit(five)}}", docxplate.SeverityError, "invalid slice modifier [:limit(five)]"},
+		{"{{Items.Name :sort(Amount,down)}}", docxplate.SeverityError, "invalid slice modifier [:sort(Amount,down)]"},
+		{"{{Items.Name :where(Active)}}", docxplate.SeverityError, "invalid slice modifier [:where(Active)]"},
+		{"{{Friends.Name}} and {{Pets.Name}}", docxplate.SeverityInfo, "row mixes keys of Friends, Pets: only one of them can be a slice"},
+		{"{{Friends.#number}} and {{Pets.#number}}", docxplate.SeverityWarning, "row mixes keys of different slices: Friends, Pets"},
+		{"Broken {{Name", docxplate.SeverityError, "unbalanced braces: \"{{\" without \"}}\""},
+		{"Broken Name}}", docxplate.SeverityError, "unbalanced braces: \"}}\" without \"{{\""},
+		{"{{Name :upper :title}}", docxplate.SeverityWarning, "not a valid placeholder"},
+		{"{{Bad :empyt:remove:row}}", docxplate.SeverityError, "unknown trigger word [:empyt]"},
+	}
+
+	for _, c := range cases {
+		assertFixtureLines(t, "lint.docx", c.text)
+	}
+
+	tdoc, err := docxplate.OpenTemplate("test-data/lint.docx")
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+	list, err := tdoc.Lint()
+	if err != nil {
+		t.Fatalf("Lint: %s", err)
+	}
+
+	for _, c := range cases {
+		var found bool
+		for _, d := range list {
+			if d.Severity == c.severity && strings.Contains(d.Message, c.message) {
+				found = true
+				if d.Part != "document" || !strings.Contains(d.Text, c.text) {
+					t.Errorf("[%s] wrong location: %s", c.text, d.Location)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("[%s] expected %s %q, got: %v", c.text, c.severity, c.message, list)
+		}
+	}
+
+	// keys of struct and slice in one row are fine: hint only, not a warning.
+	// Trigger typo is told once, not as invalid trigger too
+	var typos int
+	for _, d := range list {
+		if d.Text == "{{Friends.Name}} and {{Pets.Name}}" && d.Severity != docxplate.SeverityInfo {
+			t.Errorf("hint expected for dotted keys, got: %s", d)
+		}
+		if d.Text == "{{Bad :empyt:remove:row}}" {
+			typos++
+		}
+	}
+	if typos != 1 {
+		t.Errorf("one diagnostic expected for trigger typo, got %d: %v", typos, list)
+	}
+}
+
+// TestLintSeverity - the worse the greater, so diagnostics are filtered by threshold
+func TestLintSeverity(t *testing.T) {
+	if !(docxplate.SeverityInfo < docxplate.SeverityWarning && docxplate.SeverityWarning < docxplate.SeverityError) {
+		t.Fatalf("severities expected in order info < warning < error")
+	}
+
+	var zero docxplate.Severity
+	if zero != docxplate.SeverityInfo || zero.String() != "info" {
+		t.Fatalf("zero severity expected info, got %s", zero)
+	}
+}
+
+func TestLintValid(t *testing.T) {
+	filenames := []string{
+		"vmerge.docx",
+		"formatters.docx",
+		"header-footer.docx",
+		"user.template-with-images.docx",
+	}
+
+	for _, fname := range filenames {
+		ctpl, err := docxplate.CompileTemplate("test-data/" + fname)
+		if err != nil {
+			t.Fatalf("[%s] CompileTemplate: %s", fname, err)
+		}
+		if list := ctpl.Lint(); len(list) > 0 {
+			t.Errorf("[%s] valid template expected, got: %v", fname, list)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package docxplate_test
+
+import (
+	"strings"
+	"testing"
+)
+
+// friends of loop keys tests
+func loopFriends() map[string]any {
+	return map[string]any{
+		"Friends": []map[string]any{
+			{"Name": "Alice"},
+			{"Name": "Bob"},
+			{"Name": "Cecilia"},
+		},
+		"Tags": []string{"red", "green"},
+	}
+}
+
+func TestLoopKeys(t *testing.T) {
+	var tt = []struct {
+		text     string // paragraph of fixture
+		expected []string
+	}{
+		{
+			"Number: {{Friends.#number}}. {{Friends.Name}} ({{Friends.#index}} of {{Friends.#count}})",
+			[]string{"Number: 1. Alice (0 of 3)", "Number: 2. Bob (1 of 3)", "Number: 3. Cecilia (2 of 3)"},
+		},
+		{
+			"First last: {{Friends.Name}} {{Friends.#first}} {{Friends.#last}}",
+			[]string{"First last: Alice true false", "First last: Bob false false", "First last: Cecilia false true"},
+		},
+		{
+			"Tag: {{Tags.#number}}. {{Tags}}",
+			[]string{"Tag: 1. red", "Tag: 2. green"},
+		},
+		{
+			"Not last: {{Friends.Name}}{{?Friends.#last :=(true):remove:row}}",
+			[]string{"Not last: Alice", "Not last: Bob"},
+		},
+		{
+			"First only: {{Friends.Name :if(#first):remove:row}}",
+			[]string{"First only: Alice"},
+		},
+	}
+
+	tdoc := renderFixture(t, "loops.docx", loopFriends())
+	for _, tc := range tt {
+		assertFixtureLines(t, "loops.docx", tc.text)
+		label, _, _ := strings.Cut(tc.text, ": ")
+
+		lines := renderedLines(tdoc.Plaintext(), label+": ")
+		if strings.Join(lines, "|") != strings.Join(tc.expected, "|") {
+			t.Fatalf("%s: expected %q, got: %q", label, tc.expected, lines)
+		}
+	}
+}
+
+// TestLoopKeysEach - loop keys of {{#each}} item, separator after the last one removed
+func TestLoopKeysEach(t *testing.T) {
+	assertFixtureLines(t, "loops.docx",
+		"{{#each Friends}}",
+		"Each: {{.#number}}/{{.#count}} {{.Name}}",
+		"Each: ---{{?.#last :=(true):remove:paragraph}}",
+	)
+
+	tdoc := renderFixture(t, "loops.docx", loopFriends())
+
+	expected := []string{"Each: 1/3 Alice", "Each: ---", "Each: 2/3 Bob", "Each: ---", "Each: 3/3 Cecilia"}
+	lines := renderedLines(tdoc.Plaintext(), "Each: ")
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected %q, got: %q", expected, lines)
+	}
+}
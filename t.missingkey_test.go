@@ -0,0 +1,117 @@
+package docxplate_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bobiverse/docxplate"
+)
+
+func TestMissingKeyKeep(t *testing.T) {
+	tdoc, _ := docxplate.OpenTemplate("test-data/header-footer.docx")
+	if err := tdoc.Render(struct{ Dummy string }{}); err != nil {
+		t.Fatalf("default policy must not fail: %s", err)
+	}
+	if !strings.Contains(tdoc.Plaintext(), "{{Name}}") {
+		t.Fatalf("default policy must keep placeholders:\n%s", tdoc.Plaintext())
+	}
+}
+
+func TestMissingKeyBlank(t *testing.T) {
+	tdoc, _ := docxplate.OpenTemplate("test-data/header-footer.docx")
+	err := tdoc.Render(struct{ Name string }{Name: "Alice"}, docxplate.WithMissingKey(docxplate.MissingKeyBlank))
+	if err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+
+	plaintext := tdoc.Plaintext()
+	if strings.Contains(plaintext, "{{") {
+		t.Fatalf("placeholders must be blanked:\n%s", plaintext)
+	}
+	if !strings.Contains(plaintext, "Alice") {
+		t.Fatalf("known placeholders must be replaced:\n%s", plaintext)
+	}
+	if !strings.Contains(plaintext, "– this should not be replaced") {
+		t.Fatalf("text around blanked placeholder must stay:\n%s", plaintext)
+	}
+}
+
+// TestMissingKeyBlankNoParams - rendered template is not rendered again without its options
+func TestMissingKeyBlankNoParams(t *testing.T) {
+	tdoc, _ := docxplate.OpenTemplate("test-data/header-footer.docx")
+	err := tdoc.Render(map[string]any{}, docxplate.WithMissingKey(docxplate.MissingKeyBlank))
+	if err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+
+	if plaintext := tdoc.Plaintext(); strings.Contains(plaintext, "{{") {
+		t.Fatalf("placeholders must be blanked:\n%s", plaintext)
+	}
+	if list := tdoc.Placeholders(); len(list) > 0 {
+		t.Fatalf("no placeholders expected, got: %v", list)
+	}
+}
+
+func TestMissingKeyFail(t *testing.T) {
+	tdoc, _ := docxplate.OpenTemplate("test-data/header-footer.docx")
+	err := tdoc.Render(struct{ Dummy string }{}, docxplate.WithMissingKey(docxplate.MissingKeyFail))
+
+	var unresolvedErr *docxplate.UnresolvedError
+	if !errors.As(err, &unresolvedErr) {
+		t.Fatalf("expected *UnresolvedError, got: %v", err)
+	}
+
+	found := map[string]bool{}
+	for _, up := range unresolvedErr.Placeholders {
+		found[up.Part+" "+up.Placeholder] = true
+
+		// document placeholders of this template are all in lists
+		kind := docxplate.LocationListItem
+		if up.Part != "document" {
+			kind = docxplate.LocationParagraph
+		}
+		if up.Kind != kind {
+			t.Errorf("[%s] %s: expected %s, got %s", up.Part, up.Placeholder, kind, up.Kind)
+		}
+		if !strings.Contains(up.Text, up.Placeholder) {
+			t.Errorf("[%s] %s: context text must hold placeholder, got %q", up.Part, up.Placeholder, up.Text)
+		}
+	}
+	for _, must := range []string{
+		"header1 {{Name}}",
+		"footer1 {{Name}}",
+		"document {{Name}}",
+		"document {{NotReplacable}}",
+		"document {{NotReplacable , }}",
+	} {
+		if !found[must] {
+			t.Errorf("unresolved placeholder %q not reported: %s", must, err)
+		}
+	}
+}
+
+func TestMissingKeyFailTableCell(t *testing.T) {
+	ctpl, err := docxplate.CompileTemplate("test-data/vmerge.docx")
+	if err != nil {
+		t.Fatalf("CompileTemplate: %s", err)
+	}
+
+	_, err = ctpl.Render(`{"Friends": [{"Name": "Bob", "Age": 28}]}`, docxplate.WithMissingKey(docxplate.MissingKeyFail))
+	var unresolvedErr *docxplate.UnresolvedError
+	if !errors.As(err, &unresolvedErr) {
+		t.Fatalf("expected *UnresolvedError, got: %v", err)
+	}
+	if len(unresolvedErr.Placeholders) != 1 {
+		t.Fatalf("expected only {{Name :vmerge}} unresolved, got: %s", err)
+	}
+	up := unresolvedErr.Placeholders[0]
+	if up.Key != "Name" || up.Part != "document" || up.Kind != docxplate.LocationTableCell {
+		t.Fatalf("expected Name in document table cell, got: %s %s %s", up.Key, up.Part, up.Kind)
+	}
+
+	// all values given
+	if _, err := ctpl.Render(vmergeUser(), docxplate.WithMissingKey(docxplate.MissingKeyFail)); err != nil {
+		t.Fatalf("no placeholders left, but got: %s", err)
+	}
+}
@@ -0,0 +1,217 @@
+package docxplate_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bobiverse/docxplate"
+)
+
+// nestedUser - three levels of friends
+func nestedUser() User {
+	return User{Name: "Alice", Friends: []*User{
+		{Name: "Bob", Friends: []*User{
+			{Name: "Cecilia", Friends: []*User{{Name: "Cecilia-1"}, {Name: "Cecilia-2"}}},
+			{Name: "Den", Friends: []*User{{Name: "Den-1"}}},
+		}},
+		{Name: "Edgar", Friends: []*User{
+			{Name: "Frank", Friends: []*User{{Name: "Frank-1"}, {Name: "Frank-2"}, {Name: "Frank-3"}}},
+		}},
+	}}
+}
+
+func TestNestedRows(t *testing.T) {
+	var tt = []struct {
+		text     string // paragraph of fixture
+		expected []string
+	}{
+		{
+			"Deep: {{Friends.Friends.Friends.Name}}",
+			[]string{"Deep: Cecilia-1", "Deep: Cecilia-2", "Deep: Den-1", "Deep: Frank-1", "Deep: Frank-2", "Deep: Frank-3"},
+		},
+		{
+			// parent values are repeated in every row of nested slice
+			"Parents: {{Friends.Name}} / {{Friends.Friends.Name :upper}} / {{Friends.Friends.Friends.Name}}",
+			[]string{
+				"Parents: Bob / CECILIA / Cecilia-1",
+				"Parents: Bob / CECILIA / Cecilia-2",
+				"Parents: Bob / DEN / Den-1",
+				"Parents: Edgar / FRANK / Frank-1",
+				"Parents: Edgar / FRANK / Frank-2",
+				"Parents: Edgar / FRANK / Frank-3",
+			},
+		},
+		{
+			"Deep inline: {{Friends.Friends.Friends.Name , }}",
+			[]string{"Deep inline: Cecilia-1, Cecilia-2, Den-1, Frank-1, Frank-2, Frank-3"},
+		},
+	}
+
+	tdoc := renderFixture(t, "nested.docx", nestedUser())
+	for _, tc := range tt {
+		assertFixtureLines(t, "nested.docx", tc.text)
+		label, _, _ := strings.Cut(tc.text, ": ")
+
+		lines := renderedLines(tdoc.Plaintext(), label+": ")
+		if strings.Join(lines, "\n") != strings.Join(tc.expected, "\n") {
+			t.Fatalf("%s: expected:\n%s\ngot:\n%s", label, strings.Join(tc.expected, "\n"), strings.Join(lines, "\n"))
+		}
+	}
+}
+
+// TestNestedInline - inline values of nested slice are of their row item only
+func TestNestedInline(t *testing.T) {
+	assertFixtureLines(t, "nested.docx", "Inline: {{Cs.Name}} {{Cs.Items.T , }}")
+
+	data := map[string]any{
+		"Cs": []map[string]any{
+			{"Name": "c1", "Items": []map[string]any{{"T": "i1"}, {"T": "i2"}}},
+			{"Name": "c2", "Items": []map[string]any{{"T": "i3"}}},
+			{"Name": "c3", "Items": []map[string]any{}},
+		},
+	}
+
+	tdoc := renderFixture(t, "nested.docx", data)
+
+	expected := []string{"Inline: c1 i1, i2", "Inline: c2 i3", "Inline: c3 "}
+	lines := renderedLines(tdoc.Plaintext(), "Inline: ")
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected %q, got: %q", expected, lines)
+	}
+}
+
+func TestNestedMapsAndSlices(t *testing.T) {
+	type Address struct {
+		Street string
+		City   string
+	}
+	type Company struct {
+		Labels    map[string]string
+		Offices   map[string]Address
+		Scores    [3]int
+		Matrix    [][]string
+		Anything  any
+		Teams     []map[string][]Address
+		Signature []byte
+	}
+
+	company := Company{
+		Labels:    map[string]string{"b": "Beta", "a": "Alpha"},
+		Offices:   map[string]Address{"HQ": {Street: "Main 1", City: "Riga"}},
+		Scores:    [3]int{7, 8, 9},
+		Matrix:    [][]string{{"a1", "a2"}, {"b1"}},
+		Anything:  []Address{{City: "Tartu"}, {City: "Vilnius"}},
+		Signature: []byte("signed"),
+		Teams: []map[string][]Address{
+			{"Members": {{City: "Oslo"}, {City: "Bergen"}}},
+			{"Members": {{City: "Paris"}}},
+		},
+	}
+
+	var tt = []struct {
+		text     string // paragraph of fixture
+		expected []string
+	}{
+		{"Labels: {{Labels.a}} {{Labels.b}}", []string{"Labels: Alpha Beta"}},
+		{"Offices: {{Offices.HQ.Street}}, {{Offices.HQ.City}}", []string{"Offices: Main 1, Riga"}},
+		{"Scores: {{Scores}}", []string{"Scores: 7", "Scores: 8", "Scores: 9"}},
+		{"Matrix: {{Matrix}}", []string{"Matrix: a1", "Matrix: a2", "Matrix: b1"}},
+		{"Anything: {{Anything.City , }}", []string{"Anything: Tartu, Vilnius"}},
+		{"Teams: {{Teams.Members.City}}", []string{"Teams: Oslo", "Teams: Bergen", "Teams: Paris"}},
+		{"Signature: {{Signature}}", []string{"Signature: signed"}},
+	}
+
+	tdoc := renderFixture(t, "nested.docx", company)
+	for _, tc := range tt {
+		assertFixtureLines(t, "nested.docx", tc.text)
+		label, _, _ := strings.Cut(tc.text, ": ")
+
+		lines := renderedLines(tdoc.Plaintext(), label+": ")
+		if strings.Join(lines, "\n") != strings.Join(tc.expected, "\n") {
+			t.Fatalf("%s: expected:\n%s\ngot:\n%s", label, strings.Join(tc.expected, "\n"), strings.Join(lines, "\n"))
+		}
+	}
+}
+
+// TestNestedMapInput - nested Go values in map input are collected the same way
+func TestNestedMapInput(t *testing.T) {
+	assertFixtureLines(t, "nested.docx", "Groups: {{Groups.Users.Name}} ({{Groups.Title}})")
+
+	tdoc := renderFixture(t, "nested.docx", map[string]any{
+		"Groups": []map[string]any{
+			{"Title": "Admins", "Users": []User{{Name: "Alice"}, {Name: "Bob"}}},
+			{"Title": "Guests", "Users": []*User{{Name: "Cecilia"}}},
+		},
+	})
+
+	expected := []string{"Groups: Alice (Admins)", "Groups: Bob (Admins)", "Groups: Cecilia (Guests)"}
+	lines := renderedLines(tdoc.Plaintext(), "Groups: ")
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(lines, "\n"))
+	}
+}
+
+// TestNestedRowsEmpty - parent item with empty or absent nested slice gets
+// a row of its own, keys of nested slice are left to missing key policy
+func TestNestedRowsEmpty(t *testing.T) {
+	assertFixtureLines(t, "nested.docx", "Empty nested: X1 {{A.Name}} / {{A.B.Name}}", "Levels: {{L.Name}} / {{L.M.Name}} / {{L.M.N.Name}}")
+
+	data := map[string]any{
+		"A": []map[string]any{
+			{"Name": "a1", "B": []map[string]any{{"Name": "b1"}}},
+			{"Name": "a2", "B": []map[string]any{}},
+			{"Name": "a3"},
+			{"Name": "a4", "B": []map[string]any{{"Name": "b2"}, {"Name": "b3"}}},
+		},
+		"L": []map[string]any{
+			{"Name": "l1", "M": []map[string]any{
+				{"Name": "m1", "N": []map[string]any{{"Name": "n1"}, {"Name": "n2"}}},
+				{"Name": "m2", "N": []map[string]any{}},
+			}},
+			{"Name": "l2", "M": []map[string]any{}},
+			{"Name": "l3"},
+		},
+	}
+
+	tdoc := renderFixture(t, "nested.docx", data)
+	expected := []string{
+		"Empty nested: X1 a1 / b1",
+		"Empty nested: X1 a2 / {{A.2.B.Name}}",
+		"Empty nested: X1 a3 / {{A.3.B.Name}}",
+		"Empty nested: X1 a4 / b2",
+		"Empty nested: X1 a4 / b3",
+	}
+	if lines := renderedLines(tdoc.Plaintext(), "Empty nested: "); strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(lines, "\n"))
+	}
+
+	tdoc = renderFixture(t, "nested.docx", data, docxplate.WithMissingKey(docxplate.MissingKeyBlank))
+	expected = []string{
+		"Levels: l1 / m1 / n1",
+		"Levels: l1 / m1 / n2",
+		"Levels: l1 / m2 / ",
+		"Levels: l2 /  / ",
+		"Levels: l3 /  / ",
+	}
+	if lines := renderedLines(tdoc.Plaintext(), "Levels: "); strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(lines, "\n"))
+	}
+
+	tdoc, _ = docxplate.OpenTemplate("test-data/nested.docx")
+	err := tdoc.Render(data, docxplate.WithMissingKey(docxplate.MissingKeyFail))
+	var unresolvedErr *docxplate.UnresolvedError
+	if !errors.As(err, &unresolvedErr) {
+		t.Fatalf("expected *UnresolvedError, got: %v", err)
+	}
+	var keys []string
+	for _, p := range unresolvedErr.Placeholders {
+		if strings.HasPrefix(p.Key, "A.") || strings.HasPrefix(p.Key, "L.") {
+			keys = append(keys, p.Key)
+		}
+	}
+	expectedKeys := "A.2.B.Name A.3.B.Name L.1.M.2.N.Name L.2.M.Name L.2.M.N.Name L.3.M.Name L.3.M.N.Name"
+	if strings.Join(keys, " ") != expectedKeys {
+		t.Fatalf("expected unresolved %s, got: %q", expectedKeys, keys)
+	}
+}
@@ -1,8 +1,11 @@
 package docxplate
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestAnyToParamsMapStringString(t *testing.T) {
@@ -57,3 +60,247 @@ func TestAnyToParamsMapStringAny(t *testing.T) {
 	}
 
 }
+
+func TestStructToParamsTags(t *testing.T) {
+	type Address struct {
+		Street string
+		City   string `docx:"town"`
+	}
+	type Base struct {
+		ID      int
+		Created string `json:"created"` // json tags are not used
+		Token   string `json:"-"`
+		Note    string // conflicts with outer Note, outer wins
+	}
+	type Order struct {
+		Base
+		Name     string  `docx:"name"`
+		Phone    string  `docx:",omitempty"`
+		Note     string  `docx:",omitempty"`
+		Secret   string  `docx:"-"`
+		Dash     string  `docx:"-,"`
+		Address  Address `docx:",inline"`
+		Shipping Address `docx:"ship"`
+		hidden   string
+	}
+
+	order := Order{
+		Base:     Base{ID: 7, Created: "2024-01-02", Token: "t0", Note: "base"},
+		Name:     "Alice",
+		Note:     "fragile",
+		Secret:   "hunter2",
+		Dash:     "dash",
+		Address:  Address{Street: "Main 1", City: "Riga"},
+		Shipping: Address{Street: "Side 2", City: "Tartu"},
+		hidden:   "hidden",
+	}
+	params := StructToParams(order)
+
+	expected := map[string]string{
+		"ID":          "7",
+		"Created":     "2024-01-02",
+		"Token":       "t0",
+		"name":        "Alice",
+		"Note":        "fragile",
+		"-":           "dash",
+		"Street":      "Main 1",
+		"town":        "Riga",
+		"ship.Street": "Side 2",
+		"ship.town":   "Tartu",
+	}
+	found := map[string]string{}
+	params.WalkWithEnd(func(p *Param) bool {
+		if p.Type == StringParam {
+			found[p.AbsoluteKey] = p.Value
+		}
+		return false
+	})
+
+	for key, val := range expected {
+		if found[key] != val {
+			t.Fatalf("param `%s`: expected [%s], found [%s] in %v", key, val, found[key], found)
+		}
+	}
+	if len(found) != len(expected) {
+		t.Fatalf("expected %d params, found %d: %v", len(expected), len(found), found)
+	}
+}
+
+func TestStructToParamsEmbeddedConflict(t *testing.T) {
+	type A struct{ Name, Title string }
+	type B struct {
+		Name  string
+		Title string `docx:"Title"`
+	}
+	type C struct {
+		A
+		*B
+	}
+
+	// same depth: Name is ambiguous and dropped, tagged Title wins
+	params := StructToParams(C{A: A{Name: "a", Title: "a"}, B: &B{Name: "b", Title: "b"}})
+	if params.Len() != 1 || params.Get("Title") != "b" {
+		t.Fatalf("expected only tagged Title=b, got: %v", params)
+	}
+
+	// nil embedded pointer has no params
+	params = StructToParams(C{A: A{Name: "a", Title: "a"}})
+	if params.Len() != 0 {
+		t.Fatalf("Title of nil *B must dominate without value, got %d params", params.Len())
+	}
+}
+
+func TestJSONToParamsNumbers(t *testing.T) {
+	params := JSONToParams([]byte(`{"Big": 1000000, "Huge": 12345678901234567890, "Price": 12.50, "Paid": true}`))
+
+	expected := map[string]string{
+		"Big":   "1000000",
+		"Huge":  "12345678901234567890",
+		"Price": "12.50",
+		"Paid":  "true",
+	}
+	for key, val := range expected {
+		if v := params.Get(key); v != val {
+			t.Fatalf("param `%s`: expected [%s], found [%v]", key, val, v)
+		}
+	}
+
+	for _, p := range params {
+		switch p.Key {
+		case "Paid":
+			if p.TypedValue != true {
+				t.Fatalf("param `%s` typed value: expected bool, found %T", p.Key, p.TypedValue)
+			}
+		default:
+			if _, ok := p.TypedValue.(json.Number); !ok {
+				t.Fatalf("param `%s` typed value: expected json.Number, found %T", p.Key, p.TypedValue)
+			}
+		}
+	}
+
+	if params, err := jsonToParams([]byte(`{"Name": "Alice"} trailing`)); err == nil {
+		t.Fatalf("data after JSON object must fail, got: %v", params)
+	}
+}
+
+func TestStructToParamsTypedValues(t *testing.T) {
+	created := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	type inner struct{ Level int8 }
+	params := StructToParams(struct {
+		inner
+		Name    string
+		Price   float64
+		Paid    bool
+		Created time.Time
+		Image   Image
+	}{
+		inner:   inner{Level: 3},
+		Name:    "Alice",
+		Price:   1000000,
+		Paid:    true,
+		Created: created,
+		Image:   Image{Path: "images/avatar-1.png"},
+	})
+
+	expected := map[string]any{
+		"Level":   int8(3),
+		"Name":    "Alice",
+		"Price":   float64(1000000),
+		"Paid":    true,
+		"Created": created,
+	}
+	for _, p := range params {
+		if p.Key == "Image" {
+			if img, ok := p.TypedValue.(*Image); !ok || img.Path != "images/avatar-1.png" {
+				t.Fatalf("image param typed value: expected *Image, found %T", p.TypedValue)
+			}
+			continue
+		}
+		if p.TypedValue != expected[p.Key] {
+			t.Fatalf("param `%s` typed value: expected [%T %v], found [%T %v]", p.Key, expected[p.Key], expected[p.Key], p.TypedValue, p.TypedValue)
+		}
+	}
+
+	if v := params.Get("Created"); v != "2024-01-02T15:04:05Z" {
+		t.Fatalf("time must be formatted as in JSON, found [%v]", v)
+	}
+}
+
+// TestSliceViewParamsUntouched - rows with slice modifiers are expanded to
+// items of their view, params of template stay as in data
+func TestSliceViewParamsUntouched(t *testing.T) {
+	tdoc, err := OpenTemplate("test-data/slices.docx")
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+	names := []string{"Apples", "Pears", "Plums", "Lemons"}
+	items := []map[string]any{}
+	for i, name := range names {
+		items = append(items, map[string]any{"Name": name, "Amount": i, "Active": i%2 == 0})
+	}
+	if err := tdoc.Render(map[string]any{"Items": items, "Tags": []string{"red", "green"}}); err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+
+	var check func(holder *Param)
+	check = func(holder *Param) {
+		for _, p := range holder.Params {
+			if p.parent != holder {
+				t.Fatalf("%s: parent changed", p.AbsoluteKey)
+			}
+			if p.Key == "" || p.Key[0] == '#' && !inSlice(p.Key, loopKeys) {
+				t.Fatalf("%s: unexpected param", p.AbsoluteKey)
+			}
+			check(p)
+		}
+	}
+	for _, p := range tdoc.params {
+		check(p)
+	}
+
+	slice := tdoc.params.findByKey("Items")
+	for i, item := range slice.Params {
+		if name := item.Params.findByKey(item.AbsoluteKey + ".Name").Value; name != names[i] {
+			t.Fatalf("item %d: expected %s, got: %s", i, names[i], name)
+		}
+		if n := item.Params.findByKey(item.AbsoluteKey + "." + LoopNumber).Value; n != fmt.Sprint(i+1) {
+			t.Fatalf("item %d: expected %s %d, got: %s", i, LoopNumber, i+1, n)
+		}
+	}
+}
+
+// TestFindAllByKeyIndexed - key with slice index finds params of that item,
+// number keys of maps are kept, params found before keep their indexes
+func TestFindAllByKeyIndexed(t *testing.T) {
+	params, err := collectParams(map[string]any{
+		"Years": map[int]any{2024: map[string]any{"Items": []string{"a", "b"}}},
+		"Cs":    []map[string]any{{"Items": []string{"x", "y"}}, {"Items": []string{"z"}}},
+	})
+	if err != nil {
+		t.Fatalf("collectParams: %s", err)
+	}
+
+	all := params.FindAllByKey("Cs.Items")
+
+	var tt = []struct {
+		key      string
+		expected string
+	}{
+		{"Cs.Items", "Cs.1.Items.1:1 Cs.1.Items.2:2 Cs.2.Items.1:3"},
+		{"Cs.2.Items", "Cs.2.Items.1:1"},
+		{"Years.2024.Items", "Years.2024.Items.1:1 Years.2024.Items.2:2"},
+	}
+	for _, tc := range tt {
+		var found []string
+		for _, p := range params.FindAllByKey(tc.key) {
+			found = append(found, fmt.Sprintf("%s:%d", p.AbsoluteKey, p.Index))
+		}
+		if strings.Join(found, " ") != tc.expected {
+			t.Fatalf("%s: expected %q, got: %q", tc.key, tc.expected, found)
+		}
+	}
+
+	if p := all[len(all)-1]; p.Index != 3 {
+		t.Fatalf("%s: index of found param changed to %d", p.AbsoluteKey, p.Index)
+	}
+}
@@ -0,0 +1,108 @@
+package docxplate_test
+
+import (
+	"strings"
+	"testing"
+)
+
+// items of slice modifiers tests
+func sliceItems() map[string]any {
+	return map[string]any{
+		"Items": []map[string]any{
+			{"Name": "Apples", "Amount": 9, "Active": true},
+			{"Name": "Pears", "Amount": 120, "Active": false},
+			{"Name": "Plums", "Amount": 35, "Active": true},
+			{"Name": "Lemons", "Amount": 35, "Active": true},
+		},
+		"Tags": []string{"red", "green", "blue"},
+	}
+}
+
+func TestSliceModifiers(t *testing.T) {
+	var tt = []struct {
+		text     string // paragraph of fixture
+		expected []string
+	}{
+		{
+			"Sort: {{Items.Name :sort(Amount)}} {{Items.Amount}}",
+			[]string{"Sort: Apples 9", "Sort: Plums 35", "Sort: Lemons 35", "Sort: Pears 120"},
+		},
+		{
+			"Sort desc limit: {{Items.Name}} {{Items.Amount :sort(Amount,desc):limit(2)}}",
+			[]string{"Sort desc limit: Pears 120", "Sort desc limit: Plums 35"},
+		},
+		{
+			"Where sort: {{Items.Name :where(Active=true):sort(Name)}}",
+			[]string{"Where sort: Apples", "Where sort: Lemons", "Where sort: Plums"},
+		},
+		{
+			"Where not: {{Items.Name :where(Active!=true)}}",
+			[]string{"Where not: Pears"},
+		},
+		{
+			"Where number: {{Items.Name :where(Amount=35)}}",
+			[]string{"Where number: Plums", "Where number: Lemons"},
+		},
+		{
+			"Numbered: {{Items.#number}}. {{Items.Name :sort(Amount,desc):limit(3)}} of {{Items.#count}}",
+			[]string{"Numbered: 1. Pears of 3", "Numbered: 2. Plums of 3", "Numbered: 3. Lemons of 3"},
+		},
+		{
+			// loop keys with triggers and formatters count items as shown too
+			"Last directive: {{Items.Name :sort(Name,desc)}}{{?Items.#last :=(true):remove:row}}",
+			[]string{"Last directive: Plums", "Last directive: Pears", "Last directive: Lemons"},
+		},
+		{
+			"First directive: {{Items.Name :sort(Amount,desc)}}{{?Items.#first :=(false):remove:row}}",
+			[]string{"First directive: Pears"},
+		},
+		{
+			"Formatted: {{Items.#number :number(1)}} {{Items.#last :upper}} {{Items.Name :where(Active=true)}}",
+			[]string{"Formatted: 1.0 FALSE Apples", "Formatted: 2.0 FALSE Plums", "Formatted: 3.0 TRUE Lemons"},
+		},
+		{
+			"If last: {{Items.Name :sort(Amount):if(#last):remove:row}}",
+			[]string{"If last: Pears"},
+		},
+		{
+			"Tags: {{Tags :sort:limit(2)}}",
+			[]string{"Tags: blue", "Tags: green"},
+		},
+		{
+			"Inline: {{Items.Name , :where(Active=true):limit(2)}}",
+			[]string{"Inline: Apples, Plums"},
+		},
+		{
+			"None: {{Items.Name :where(Active=yes)}}",
+			nil,
+		},
+		{
+			// other rows of the same slice keep all items in data order
+			"Top: {{Items.#number}}. {{Items.Name :sort(Amount,desc):limit(1)}}",
+			[]string{"Top: 1. Pears"},
+		},
+		{
+			"All: {{Items.#number}}. {{Items.Name}}",
+			[]string{"All: 1. Apples", "All: 2. Pears", "All: 3. Plums", "All: 4. Lemons"},
+		},
+		{
+			// modifier in one cell orders the whole row
+			"Cell: {{Items.Name}}",
+			[]string{"Cell: Plums", "Cell: 35", "Cell: Lemons", "Cell: 35", "Cell: Apples", "Cell: 9"},
+		},
+	}
+
+	// other cell of Cell row
+	assertFixtureLines(t, "slices.docx", "Cell: {{Items.Amount :where(Active=true):sort(Amount,desc)}}")
+
+	tdoc := renderFixture(t, "slices.docx", sliceItems())
+	for _, tc := range tt {
+		assertFixtureLines(t, "slices.docx", tc.text)
+		label, _, _ := strings.Cut(tc.text, ": ")
+
+		lines := renderedLines(tdoc.Plaintext(), label+": ")
+		if strings.Join(lines, "|") != strings.Join(tc.expected, "|") {
+			t.Fatalf("%s: expected %q, got: %q", label, tc.expected, lines)
+		}
+	}
+}
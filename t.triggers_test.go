@@ -0,0 +1,498 @@
+package docxplate_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bobiverse/docxplate"
+)
+
+// order of triggers tests
+type triggerOrder struct {
+	ID     string
+	Status string
+	Total  float64
+	Note   string
+}
+
+func triggerOrders() map[string]any {
+	return map[string]any{
+		"Orders": []triggerOrder{
+			{ID: "A1", Status: "paid", Total: 100, Note: "gift"},
+			{ID: "A2", Status: "cancelled", Total: 99.5},
+			{ID: "A3", Status: "sent", Total: 1000, Note: "urgent gift"},
+			{ID: "A4", Status: "cancelled", Total: 0},
+		},
+	}
+}
+
+func TestTriggerComparisons(t *testing.T) {
+	var tt = []struct {
+		text     string // paragraph of fixture
+		expected string // IDs of rows left
+	}{
+		{"Eq: {{Orders.ID}} {{Orders.Status :=(cancelled):remove:row}}", "A1 A3"},
+		{"Not eq: {{Orders.ID}} {{Orders.Status :!=(cancelled):remove:row}}", "A2 A4"},
+		{"Eq clear: {{Orders.ID}} {{Orders.Status :=(paid):clear:row}}", "A2 A3 A4"},
+		{"Contains: {{Orders.ID}} {{Orders.Status :contains(cel):remove:row}}", "A1 A3"},
+		{"Match: {{Orders.ID}} {{Orders.Status :match(^(paid|sent)$):remove:row}}", "A2 A4"},
+		{"Match prefix: {{Orders.ID}} {{Orders.Status :match(^c):remove:row}}", "A1 A3"},
+		{"Gt: {{Orders.ID}} {{Orders.Total :gt(100):remove:row}}", "A1 A2 A4"},
+		{"Lt: {{Orders.ID}} {{Orders.Total :lt(100):remove:row}}", "A1 A3"},
+		{"Eq zero: {{Orders.ID}} {{Orders.Total :=(0):remove:row}}", "A1 A2 A3"},
+		{"Eq zero decimals: {{Orders.ID}} {{Orders.Total :=(0.00):remove:row}}", "A1 A2 A3"},
+		{"Not eq decimal: {{Orders.ID}} {{Orders.Total :!=(100.0):remove:row}}", "A1"},
+		{"Gt decimal: {{Orders.ID}} {{Orders.Total :gt(99.9):remove:row}}", "A2 A4"},
+		// text is compared as text
+		{"Text gt: {{Orders.ID}} {{Orders.Note :gt(h):remove:row}}", "A1 A2 A4"},
+		{"Text eq: {{Orders.ID}} {{Orders.Note :=():remove:row}}", "A1 A3"},
+		{"Text not eq: {{Orders.ID}} {{Orders.Note :!=():remove:row}}", "A2 A4"},
+	}
+
+	tdoc := renderFixture(t, "triggers.docx", triggerOrders())
+	plaintext := tdoc.Plaintext()
+	for _, tc := range tt {
+		assertFixtureLines(t, "triggers.docx", tc.text)
+		label, _, _ := strings.Cut(tc.text, ": ")
+
+		var ids []string
+		for _, line := range renderedLines(plaintext, label+": ") {
+			if id := strings.Fields(strings.TrimPrefix(line, label+": ")); len(id) > 0 {
+				ids = append(ids, id[0])
+			}
+		}
+		if strings.Join(ids, " ") != tc.expected {
+			t.Fatalf("%s: expected rows %q, got %q", label, tc.expected, strings.Join(ids, " "))
+		}
+	}
+}
+
+func TestTriggerComparisonsParsed(t *testing.T) {
+	tr := docxplate.NewParamTrigger([]byte(":upper:match(^(a|b)$):remove:row"))
+	if tr == nil {
+		t.Fatalf("trigger expected")
+	}
+	if tr.On != docxplate.TriggerOnMatch || tr.Value != "^(a|b)$" {
+		t.Fatalf("expected :match with value ^(a|b)$, got: %s %s", tr.On, tr.Value)
+	}
+	if s := tr.String(); s != ":match(^(a|b)$):remove:row" {
+		t.Fatalf("unexpected trigger string: %s", s)
+	}
+
+	// bare := is not :=() comparing with empty value
+	for _, raw := range []string{":=:remove:row", ":=():remove:row"} {
+		if s := docxplate.NewParamTrigger([]byte(raw)).String(); s != raw {
+			t.Fatalf("expected trigger string %s, got: %s", raw, s)
+		}
+	}
+}
+
+func TestTriggerComparisonsInvalid(t *testing.T) {
+	assertFixtureLines(t, "triggers.invalid.docx", "{{Name :gt:remove:row}}", "{{Name :match([a-):remove:row}}", "{{Name :empty(x):remove:row}}")
+
+	tdoc, _ := docxplate.OpenTemplate("test-data/triggers.invalid.docx")
+	err := tdoc.Render(map[string]any{"Name": "Alice"})
+
+	var triggers []string
+	for _, err := range joinedErrors(err) {
+		var triggerErr *docxplate.TriggerError
+		if errors.As(err, &triggerErr) {
+			triggers = append(triggers, triggerErr.Trigger)
+		}
+	}
+
+	expected := []string{":gt:remove:row", ":match([a-):remove:row", ":empty(x):remove:row"}
+	if strings.Join(triggers, " ") != strings.Join(expected, " ") {
+		t.Fatalf("expected *TriggerError of %q, got: %v", expected, err)
+	}
+}
+
+// TestTriggerStrictEmpty - :empty on missing key fires unless WithStrictEmpty
+func TestTriggerStrictEmpty(t *testing.T) {
+	assertFixtureLines(t, "triggers.docx", "Void: {{VoidParam :empty:remove:paragraph}}", "Void unknown: {{VoidParam :unknown:remove:paragraph}}")
+
+	var tt = []struct {
+		label   string
+		opts    []docxplate.Option
+		removed bool
+	}{
+		{"Void", nil, true},
+		{"Void", []docxplate.Option{docxplate.WithStrictEmpty()}, false},
+		{"Void unknown", nil, true},
+		{"Void unknown", []docxplate.Option{docxplate.WithStrictEmpty()}, true},
+	}
+
+	for _, tc := range tt {
+		tdoc := renderFixture(t, "triggers.docx", map[string]any{"Name": "Alice"}, tc.opts...)
+
+		removed := len(renderedLines(tdoc.Plaintext(), tc.label+": ")) == 0
+		if removed != tc.removed {
+			t.Fatalf("%s (%d options): expected removed=%v, got: %v", tc.label, len(tc.opts), tc.removed, removed)
+		}
+	}
+
+	// null is empty by default, not in strict mode
+	tdoc := renderFixture(t, "triggers.docx", []byte(`{"VoidParam": null}`))
+	if lines := renderedLines(tdoc.Plaintext(), "Void: "); len(lines) > 0 {
+		t.Fatalf(":empty on null key must fire by default, got: %q", lines)
+	}
+	tdoc = renderFixture(t, "triggers.docx", []byte(`{"VoidParam": null}`), docxplate.WithStrictEmpty())
+	if lines := renderedLines(tdoc.Plaintext(), "Void: "); len(lines) != 1 {
+		t.Fatalf(":empty on null key must not fire with WithStrictEmpty, got: %q", lines)
+	}
+}
+
+// TestTriggerStates - :unknown, :null and :empty (strict) fire only on own state
+func TestTriggerStates(t *testing.T) {
+	assertFixtureLines(t, "triggers.docx",
+		"Unknown: {{Phone :unknown:remove:row}}",
+		"Null: {{Phone :null:remove:row}}",
+		"Empty: {{Phone :empty:remove:row}}",
+		"Eq empty: {{Phone :=():remove:row}}",
+		"Set: {{Phone :=:remove:row}}",
+		"Not x: {{Phone :!=(x):remove:row}}",
+		"Contact phone: {{Contacts.Name}} {{Contacts.Phone :null:remove:row}}",
+		"Contact name: {{Contacts.Name :empty:remove:row}}",
+	)
+
+	type contact struct {
+		Name  string
+		Phone *string
+	}
+	phone := "+371 2000"
+
+	var tt = []struct {
+		json  string
+		label string
+		fires bool
+	}{
+		{`{"Phone": "x"}`, "Unknown", false},
+		{`{"Phone": "x"}`, "Null", false},
+		{`{"Phone": "x"}`, "Empty", false},
+		{`{}`, "Unknown", true},
+		{`{}`, "Null", false},
+		{`{}`, "Empty", false},
+		{`{"Phone": null}`, "Unknown", false},
+		{`{"Phone": null}`, "Null", true},
+		{`{"Phone": null}`, "Empty", false},
+		{`{"Phone": ""}`, "Unknown", false},
+		{`{"Phone": ""}`, "Null", false},
+		{`{"Phone": ""}`, "Empty", true},
+		{`{"Phone": null}`, "Eq empty", false},
+		{`{}`, "Not x", false},
+		{`{"Phone": ""}`, "Eq empty", true},
+		// bare := fires when key is set
+		{`{"Phone": "x"}`, "Set", true},
+		{`{"Phone": ""}`, "Set", true},
+		{`{"Phone": null}`, "Set", false},
+		{`{}`, "Set", false},
+	}
+
+	for _, tc := range tt {
+		tdoc := renderFixture(t, "triggers.docx", []byte(tc.json), docxplate.WithStrictEmpty())
+
+		removed := len(renderedLines(tdoc.Plaintext(), tc.label+": ")) == 0
+		if removed != tc.fires {
+			t.Fatalf("%s %s: expected fired=%v, got: %v", tc.json, tc.label, tc.fires, removed)
+		}
+	}
+
+	// nil pointer field is null
+	tdoc := renderFixture(t, "triggers.docx", map[string]any{
+		"Contacts": []contact{{Name: "Alice", Phone: &phone}, {Name: "Bob"}},
+	})
+	if lines := renderedLines(tdoc.Plaintext(), "Contact phone: "); len(lines) != 1 || !strings.Contains(lines[0], "Alice") {
+		t.Fatalf("expected only Alice row, got: %q", lines)
+	}
+
+	// key inside of empty slice is empty, not unknown
+	tdoc = renderFixture(t, "triggers.docx", map[string]any{"Contacts": []contact{}})
+	if lines := renderedLines(tdoc.Plaintext(), "Contact name: "); len(lines) != 0 {
+		t.Fatalf("expected row removed, got: %q", lines)
+	}
+}
+
+// TestTriggerStatesRows - state of key is the one of row item,
+// not of key found in other items
+func TestTriggerStatesRows(t *testing.T) {
+	items := []byte(`{"Items": [{"Name": "a", "Phone": ""}, {"Name": "b"}, {"Name": "c", "Phone": null}, {"Name": "d", "Phone": "1"}]}`)
+
+	var tt = []struct {
+		text     string   // paragraph of fixture
+		expected []string // rows left
+	}{
+		{"Item unknown: {{Items.Name}} {{Items.Phone :unknown:remove:row}}", []string{
+			"Item unknown: a ",
+			"Item unknown: c ",
+			"Item unknown: d 1",
+		}},
+		// absent key of item is left as is, like any unknown key
+		{"Item null: {{Items.Name}} {{Items.Phone :null:remove:row}}", []string{
+			"Item null: a ",
+			"Item null: b {{Items.2.Phone :null:remove:row}}",
+			"Item null: d 1",
+		}},
+		{"Item empty: {{Items.Name}} {{Items.Phone :empty:remove:row}}", []string{
+			"Item empty: b {{Items.2.Phone :empty:remove:row}}",
+			"Item empty: c ",
+			"Item empty: d 1",
+		}},
+	}
+
+	tdoc := renderFixture(t, "triggers.docx", items, docxplate.WithStrictEmpty())
+	plaintext := tdoc.Plaintext()
+
+	for _, tc := range tt {
+		assertFixtureLines(t, "triggers.docx", tc.text)
+		label, _, _ := strings.Cut(tc.text, ": ")
+
+		if lines := renderedLines(plaintext, label+": "); !slices.Equal(lines, tc.expected) {
+			t.Fatalf("%s: expected %q, got: %q", label, tc.expected, lines)
+		}
+	}
+}
+
+func TestParamState(t *testing.T) {
+	params := docxplate.JSONToParams([]byte(`{"Name": "Alice", "Phone": "", "Fax": null, "Nicknames": [], "Friends": ["Bob"]}`))
+
+	var tt = map[string]docxplate.ParamState{
+		"Name":      docxplate.ParamStateValue,
+		"Phone":     docxplate.ParamStateEmpty,
+		"Fax":       docxplate.ParamStateNull,
+		"Nicknames": docxplate.ParamStateEmpty,
+		"Friends":   docxplate.ParamStateValue,
+	}
+
+	for _, p := range params {
+		expected, ok := tt[p.Key]
+		if !ok {
+			t.Fatalf("unexpected param: %s", p.Key)
+		}
+		if state := p.State(); state != expected {
+			t.Fatalf("%s: expected state %s, got: %s", p.Key, expected, state)
+		}
+		delete(tt, p.Key)
+	}
+	if len(tt) > 0 {
+		t.Fatalf("params not found: %v", tt)
+	}
+}
+
+// TestTriggersMultiple - triggers run in written order, the first fired one only.
+// Strict :empty, so it does not take over :unknown of missing key
+func TestTriggersMultiple(t *testing.T) {
+	assertFixtureLines(t, "triggers.docx",
+		"State first: {{Phone :empty:clear:placeholder:unknown:remove:row}}.",
+		"Value first: {{Phone :=(x):clear:placeholder:contains(x):remove:row}}.",
+		"Formatted: {{Phone :upper:!=(x):remove:row:=(x):clear:placeholder}}.",
+	)
+
+	var tt = []struct {
+		json     string
+		label    string
+		expected string // line left, "" if removed
+	}{
+		{`{"Phone": "x"}`, "State first", "State first: x."},
+		{`{"Phone": ""}`, "State first", "State first: ."},
+		{`{"Name": "x"}`, "State first", ""},
+		{`{"Phone": null}`, "State first", "State first: ."}, // null renders as empty text
+		{`{"Phone": "x"}`, "Value first", "Value first: ."},
+		{`{"Phone": "xy"}`, "Value first", ""},
+		{`{"Phone": "x"}`, "Formatted", "Formatted: ."},
+	}
+
+	for _, tc := range tt {
+		tdoc := renderFixture(t, "triggers.docx", []byte(tc.json), docxplate.WithStrictEmpty())
+
+		line := strings.Join(renderedLines(tdoc.Plaintext(), tc.label+": "), "\n")
+		if line != tc.expected {
+			t.Fatalf("%s %s: expected %q, got: %q", tc.json, tc.label, tc.expected, line)
+		}
+	}
+}
+
+// TestTriggersMultipleRows - README example in table rows: empty phone
+// clears its cell, row of item without phone is removed
+func TestTriggersMultipleRows(t *testing.T) {
+	assertFixtureLines(t, "triggers.docx", "Item cell: {{Items.Name}}", "{{Items.Phone :empty:clear:cell:unknown:remove:row}}")
+
+	items := []byte(`{"Items": [{"Name": "a", "Phone": ""}, {"Name": "b"}, {"Name": "c", "Phone": null}, {"Name": "d", "Phone": "1"}]}`)
+	tdoc := renderFixture(t, "triggers.docx", items, docxplate.WithStrictEmpty())
+
+	plaintext := tdoc.Plaintext()
+	expected := []string{"Item cell: a", "Item cell: c", "Item cell: d"}
+	if lines := renderedLines(plaintext, "Item cell: "); !slices.Equal(lines, expected) {
+		t.Fatalf("expected %q, got: %q", expected, lines)
+	}
+	if strings.Contains(plaintext, ":empty:clear:cell:unknown:remove:row") {
+		t.Fatalf("placeholder left in rows:\n%s", plaintext)
+	}
+}
+
+func TestTriggersMultipleParsed(t *testing.T) {
+	trs := docxplate.NewParamTriggers([]byte(":empty:clear:cell:upper:unknown:remove:row"))
+	if len(trs) != 2 {
+		t.Fatalf("expected 2 triggers, got: %d", len(trs))
+	}
+	if s := trs[0].String() + " " + trs[1].String(); s != ":empty:clear:cell :unknown:remove:row" {
+		t.Fatalf("unexpected triggers: %s", s)
+	}
+
+	p := docxplate.NewParamFromRaw([]byte("{{Phone :empty:clear:cell:unknown:remove:row}}"))
+	if p.Trigger != p.Triggers[0] || len(p.Triggers) != 2 {
+		t.Fatalf("expected Trigger to be the first of 2 triggers, got: %v", p.Triggers)
+	}
+
+	var invalid = []string{
+		":empty:clear:cell:unknown:remove",
+		":empty:unknown:remove:clear:cell:row",
+		":empty:clear:cell:gt:remove:row",
+	}
+	for _, raw := range invalid {
+		if trs := docxplate.NewParamTriggers([]byte(raw)); trs != nil {
+			t.Fatalf("%s: expected invalid, got: %v", raw, trs)
+		}
+	}
+}
+
+// TestTriggerIf - :if(Key) keeps content only if other key is true
+func TestTriggerIf(t *testing.T) {
+	assertFixtureLines(t, "triggers.docx", "Discount: {{Discount :if(IsVIP):remove:paragraph}}")
+
+	var tt = []struct {
+		json string
+		kept bool
+	}{
+		{`{"Discount": 10, "IsVIP": true}`, true},
+		{`{"Discount": 10, "IsVIP": "yes"}`, true},
+		{`{"Discount": 10, "IsVIP": 1}`, true},
+		{`{"Discount": 10, "IsVIP": false}`, false},
+		{`{"Discount": 10, "IsVIP": "false"}`, false},
+		{`{"Discount": 10, "IsVIP": 0}`, false},
+		{`{"Discount": 10, "IsVIP": ""}`, false},
+		{`{"Discount": 10, "IsVIP": null}`, false},
+		{`{"Discount": 10}`, false},
+		{`{"IsVIP": true}`, true},
+		{`{"Discount": 10, "IsVIP": ["", "555"]}`, true},
+		{`{"Discount": 10, "IsVIP": ["0"]}`, true},
+		{`{"Discount": 10, "IsVIP": ["false", "x"]}`, true},
+		{`{"Discount": 10, "IsVIP": []}`, false},
+	}
+
+	for _, tc := range tt {
+		tdoc := renderFixture(t, "triggers.docx", []byte(tc.json))
+
+		kept := len(renderedLines(tdoc.Plaintext(), "Discount: ")) > 0
+		if kept != tc.kept {
+			t.Fatalf("%s: expected kept=%v, got: %v", tc.json, tc.kept, kept)
+		}
+	}
+}
+
+func TestTriggerDirective(t *testing.T) {
+	assertFixtureLines(t, "triggers.docx",
+		"Note discount: {{?HasDiscount :remove:paragraph}}discount applies.",
+		"Note discount clear: {{?HasDiscount :clear:paragraph}}discount applies.",
+		"Note paid: {{?Status :!=(paid):remove:paragraph}}paid.",
+		"Note status: {{?Status :unknown:remove:paragraph}}{{Status}}.",
+	)
+
+	var tt = []struct {
+		label    string
+		json     string
+		expected string // line left, "" if removed
+	}{
+		{"Note discount", `{"HasDiscount": true}`, "Note discount: discount applies."},
+		{"Note discount", `{"HasDiscount": false}`, ""},
+		{"Note discount", `{}`, ""},
+		{"Note discount clear", `{"HasDiscount": false}`, ""},
+		{"Note paid", `{"Status": "paid"}`, "Note paid: paid."},
+		{"Note paid", `{"Status": "sent"}`, ""},
+		{"Note status", `{"Status": "sent"}`, "Note status: sent."},
+	}
+
+	for _, tc := range tt {
+		tdoc := renderFixture(t, "triggers.docx", []byte(tc.json))
+
+		line := strings.Join(renderedLines(tdoc.Plaintext(), tc.label+": "), "\n")
+		if line != tc.expected {
+			t.Fatalf("%s %s: expected %q, got: %q", tc.label, tc.json, tc.expected, line)
+		}
+	}
+}
+
+// TestTriggerIfRows - keys are looked up in slice item of row first
+func TestTriggerIfRows(t *testing.T) {
+	contacts := map[string]any{
+		"IsVIP": false,
+		"Contacts": []map[string]any{
+			{"Name": "Alice", "IsVIP": true, "Discount": 10},
+			{"Name": "Bob", "IsVIP": false, "Discount": 5},
+			{"Name": "Cecilia", "IsVIP": "yes", "Discount": 0},
+		},
+	}
+	names := map[string]any{
+		"IsVIP":    true,
+		"Contacts": []map[string]any{{"Name": "Alice"}, {"Name": "Bob"}},
+	}
+
+	var tt = []struct {
+		text     string // paragraph of fixture
+		params   map[string]any
+		expected []string
+	}{
+		{"VIP discount: {{Contacts.Name}} {{Contacts.Discount :if(IsVIP):remove:row}}", contacts, []string{"VIP discount: Alice 10", "VIP discount: Cecilia 0"}},
+		{"VIP: {{Contacts.Name}}{{?Contacts.IsVIP :remove:row}}", contacts, []string{"VIP: Alice", "VIP: Cecilia"}},
+		{"With discount: {{Contacts.Name}}{{?Contacts.Discount :remove:row}}", contacts, []string{"With discount: Alice", "With discount: Bob"}},
+		{"VIP names: {{Contacts.Name :if(IsVIP):remove:row}}", names, []string{"VIP names: Alice", "VIP names: Bob"}},
+	}
+
+	for _, tc := range tt {
+		assertFixtureLines(t, "triggers.docx", tc.text)
+		label, _, _ := strings.Cut(tc.text, ": ")
+
+		tdoc := renderFixture(t, "triggers.docx", tc.params)
+
+		lines := renderedLines(tdoc.Plaintext(), label+": ")
+		if strings.Join(lines, "|") != strings.Join(tc.expected, "|") {
+			t.Fatalf("%s: expected %q, got: %q", label, tc.expected, lines)
+		}
+	}
+}
+
+func TestTriggerDirectiveInspect(t *testing.T) {
+	assertFixtureLines(t, "triggers.docx", "Note discount: {{?HasDiscount :remove:paragraph}}discount applies.")
+
+	tdoc, _ := docxplate.OpenTemplate("test-data/triggers.docx")
+
+	infos, err := tdoc.Inspect()
+	if err != nil {
+		t.Fatalf("Inspect: %s", err)
+	}
+
+	var found bool
+	for _, info := range infos {
+		if info.Placeholder != "{{?HasDiscount :remove:paragraph}}" {
+			continue
+		}
+		found = true
+		if !info.Directive || info.Trigger == nil || info.Trigger.String() != ":if:remove:paragraph" {
+			t.Fatalf("expected directive with :if:remove:paragraph trigger, got: %+v", info)
+		}
+	}
+	if !found {
+		t.Fatalf("directive not found")
+	}
+
+	diags, err := tdoc.Lint()
+	if err != nil {
+		t.Fatalf("Lint: %s", err)
+	}
+	for _, d := range diags {
+		if strings.Contains(d.Placeholder, "HasDiscount") {
+			t.Fatalf("unexpected diagnostic: %s", d)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package docxplate_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bobiverse/docxplate"
+)
+
+type Money struct {
+	Cents    int
+	Currency string
+}
+
+func (m Money) DocxValue() (any, error) {
+	return fmt.Sprintf("%d.%02d %s", m.Cents/100, m.Cents%100, m.Currency), nil
+}
+
+type PhoneNumber string
+
+func (pn *PhoneNumber) DocxValue() (any, error) {
+	s := string(*pn)
+	return s[:3] + " " + s[3:], nil
+}
+
+type Address struct {
+	Street, City string
+}
+
+func (a Address) DocxValue() (any, error) {
+	return map[string]any{
+		"Line": a.Street + ", " + a.City,
+		"City": strings.ToUpper(a.City),
+	}, nil
+}
+
+type Tags string
+
+func (tags Tags) DocxValue() (any, error) {
+	return strings.Split(string(tags), ","), nil
+}
+
+type Broken struct{}
+
+func (Broken) DocxValue() (any, error) {
+	return nil, errors.New("broken value")
+}
+
+func TestDocxValuer(t *testing.T) {
+	phone := PhoneNumber("37120000000")
+	order := struct {
+		Total   Money
+		Phone   *PhoneNumber
+		Address Address
+		Tags    Tags
+		Items   []Money
+		Logo    docxplate.DocxValuer
+	}{
+		Total:   Money{Cents: 12345, Currency: "EUR"},
+		Phone:   &phone,
+		Address: Address{Street: "Main 1", City: "Riga"},
+		Tags:    "new,paid",
+		Items:   []Money{{Cents: 100, Currency: "EUR"}, {Cents: 250, Currency: "EUR"}},
+	}
+
+	var tt = []struct {
+		label    string
+		expected []string
+	}{
+		{"Total", []string{"Total: 123.45 EUR"}},
+		{"Phone", []string{"Phone: 371 20000000"}},
+		{"Address", []string{"Address: Main 1, Riga / RIGA"}},
+		{"Tags", []string{"Tags: new", "Tags: paid"}},
+		{"Items", []string{"Items: 1.00 EUR, 2.50 EUR"}},
+	}
+
+	tdoc := renderFixture(t, "valuer.docx", order)
+	for _, tc := range tt {
+		lines := renderedLines(tdoc.Plaintext(), tc.label+": ")
+		if strings.Join(lines, "\n") != strings.Join(tc.expected, "\n") {
+			t.Fatalf("%s: expected:\n%s\ngot:\n%s", tc.label, strings.Join(tc.expected, "\n"), strings.Join(lines, "\n"))
+		}
+	}
+}
+
+// TestDocxValuerPointer - struct passed by pointer gets the same params as by value
+func TestDocxValuerPointer(t *testing.T) {
+	type order struct {
+		Name   string `docx:"Customer"`
+		Total  Money
+		Secret string `docx:"-"`
+	}
+	o := order{Name: "Alice", Total: Money{Cents: 12345, Currency: "EUR"}, Secret: "s3cr3t"}
+
+	for _, data := range []any{o, &o} {
+		tdoc := renderFixture(t, "valuer.docx", data)
+
+		lines := renderedLines(tdoc.Plaintext(), "Customer: ")
+		expected := "Customer: Alice|123.45 EUR|{{Secret}}"
+		if strings.Join(lines, "\n") != expected {
+			t.Fatalf("%T: expected %q, got: %q", data, expected, lines)
+		}
+	}
+}
+
+// TestDocxValuerPointerReceiver - DocxValue of pointer receiver is found
+// for field of struct passed by value and for map value as well
+func TestDocxValuerPointerReceiver(t *testing.T) {
+	type contact struct {
+		Phone PhoneNumber
+	}
+
+	for _, data := range []any{
+		contact{Phone: "37120000000"},
+		&contact{Phone: "37120000000"},
+		map[string]any{"Phone": PhoneNumber("37120000000")},
+	} {
+		tdoc := renderFixture(t, "valuer.docx", data)
+
+		expected := "Phone: 371 20000000"
+		if lines := renderedLines(tdoc.Plaintext(), "Phone: "); len(lines) != 1 || lines[0] != expected {
+			t.Fatalf("%T: expected %q, got: %q", data, expected, lines)
+		}
+	}
+}
+
+// TestDocxValuerMap - map and slice values are checked too
+func TestDocxValuerMap(t *testing.T) {
+	tdoc := renderFixture(t, "valuer.docx", map[string]any{
+		"Total":  Money{Cents: 999, Currency: "USD"},
+		"Prices": []any{Money{Cents: 5, Currency: "USD"}, "free"},
+	})
+
+	expected := "Prices: 9.99 USD 0.05 USD, free"
+	if lines := renderedLines(tdoc.Plaintext(), "Prices: "); len(lines) != 1 || lines[0] != expected {
+		t.Fatalf("expected %q, got: %q", expected, lines)
+	}
+}
+
+func TestDocxValuerImage(t *testing.T) {
+	tdoc := renderFixture(t, "valuer.docx", map[string]any{"Logo": avatar("images/avatar-1.png")})
+
+	buf, err := tdoc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %s", err)
+	}
+	if docXML := documentXMLFromBytes(t, buf); !strings.Contains(docXML, "<v:imagedata") {
+		t.Fatalf("image of DocxValue not added")
+	}
+}
+
+type avatar string
+
+func (a avatar) DocxValue() (any, error) {
+	return &docxplate.Image{Path: string(a), Width: 20, Height: 20}, nil
+}
+
+func TestDocxValuerError(t *testing.T) {
+	tdoc, err := docxplate.OpenTemplate("test-data/valuer.docx")
+	if err != nil {
+		t.Fatalf("OpenTemplate: %s", err)
+	}
+
+	err = tdoc.Render(struct {
+		Name   string
+		Broken Broken
+	}{Name: "Alice"})
+
+	var valueErr *docxplate.ValueError
+	if !errors.As(err, &valueErr) {
+		t.Fatalf("expected *ValueError, got: %v", err)
+	}
+	if valueErr.Key != "Broken" || valueErr.Err.Error() != "broken value" {
+		t.Fatalf("value error must point to Broken, got: %s", valueErr)
+	}
+
+	// the rest is rendered anyway, failed param is left unresolved
+	expected := "Broken: Alice {{Broken}}"
+	if lines := renderedLines(tdoc.Plaintext(), "Broken: "); len(lines) != 1 || lines[0] != expected {
+		t.Fatalf("expected %q, got: %q", expected, lines)
+	}
+}
@@ -3,7 +3,9 @@ package docxplate
 import (
 	"bytes"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
 	"regexp"
 	"slices"
 	"strings"
@@ -109,10 +111,10 @@ func (xnode *xmlNode) iterate(fn func(node *xmlNode) bool) {
 }
 
 // GetContentPrefixList ..
-func (xnode xmlNode) GetContentPrefixList() (ret []string) {
+func (xnode xmlNode) GetContentPrefixList(d *delimiters) (ret []string) {
 	// log.Printf("[%s]", aurora.Yellow(xnode.Content))
 
-	matches := reParamExtract.FindAllSubmatch(xnode.Content, -1)
+	matches := d.reParam.FindAllSubmatch(xnode.Content, -1)
 	if matches == nil || matches[0] == nil || len(matches[0]) < 3 {
 		return nil
 	}
@@ -142,9 +144,12 @@ func (xnode *xmlNode) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 	n := xnode
 	for {
 		token, err := d.Token()
-		if err != nil {
+		if errors.Is(err, io.EOF) {
 			break
 		}
+		if err != nil {
+			return err
+		}
 		switch t := token.(type) {
 		case xml.StartElement:
 			sub := &xmlNode{
@@ -260,7 +265,8 @@ func (xnode *xmlNode) AllContents() []byte {
 	if xnode == nil {
 		return nil
 	}
-	buf := xnode.Content
+	// copy, so appending never writes into node's own contents
+	buf := slices.Clone(xnode.Content)
 
 	xnode.Walk(func(n *xmlNode) {
 		buf = append(buf, n.Content...)
@@ -308,7 +314,7 @@ func (xnode *xmlNode) isSingle() bool {
 }
 
 // HaveParams - does node contents contains any param
-func (xnode *xmlNode) HaveParams() bool {
+func (xnode *xmlNode) HaveParams(d *delimiters) bool {
 	buf := xnode.AllContents()
 
 	// if bytes.Contains(buf, []byte("{{")) && !bytes.Contains(buf, []byte("}}")) {
@@ -316,8 +322,8 @@ func (xnode *xmlNode) HaveParams() bool {
 	// 	log.Printf("Param node: [%+v]", xnode)
 	// }
 
-	have := bytes.Contains(buf, []byte("{{"))        // start
-	have = have && bytes.Contains(buf, []byte("}}")) // end
+	have := bytes.Contains(buf, []byte(d.open))         // start
+	have = have && bytes.Contains(buf, []byte(d.close)) // end
 	return have
 }
 
@@ -408,6 +414,31 @@ func (xnode *xmlNode) clone(parent *xmlNode) *xmlNode {
 	return xnodeCopy
 }
 
+// Copy node and all childs as they are (isNew flag kept).
+// Attrs and contents are copied too, so copy can be modified
+// without touching the original
+func (xnode *xmlNode) copyTree(parent *xmlNode) *xmlNode {
+	if xnode == nil {
+		return nil
+	}
+
+	xnodeCopy := &xmlNode{
+		XMLName: xnode.XMLName,
+		Attrs:   slices.Clone(xnode.Attrs),
+		Content: slices.Clone(xnode.Content),
+		isNew:   xnode.isNew,
+		parent:  parent,
+	}
+	if xnode.childFirst != nil {
+		xnode.childFirst.iterate(func(node *xmlNode) bool {
+			xnodeCopy.addSub(node.copyTree(xnodeCopy))
+			return false
+		})
+	}
+
+	return xnodeCopy
+}
+
 // Delete node
 func (xnode *xmlNode) delete() {
 	xnode.childLenght = 0